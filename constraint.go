@@ -0,0 +1,268 @@
+package bon
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Registry of named parameter constraints used as :name<type>
+var namedConstraints = struct {
+	sync.RWMutex
+	m map[string]func(string) bool
+}{
+	m: map[string]func(string) bool{
+		"int":   isInt,
+		"uint":  isUint,
+		"alpha": isAlpha,
+		"alnum": isAlnum,
+		"hex":   isHex,
+		"uuid":  isUUID,
+	},
+}
+
+// RegisterConstraint registers a named parameter constraint, usable in
+// patterns as :param<name>. Routes resolve constraints at registration, so
+// the constraint must be registered before the routes using it.
+func RegisterConstraint(name string, match func(value string) bool) {
+	if name == "" {
+		panic("bon: constraint name cannot be empty")
+	}
+	if strings.ContainsAny(name, "<>/") {
+		panic("bon: invalid constraint name " + name)
+	}
+	if match == nil {
+		panic("bon: constraint function cannot be nil")
+	}
+
+	namedConstraints.Lock()
+	namedConstraints.m[name] = match
+	namedConstraints.Unlock()
+}
+
+// Look up named constraint
+func lookupConstraint(name string) (func(string) bool, bool) {
+	namedConstraints.RLock()
+	match, ok := namedConstraints.m[name]
+	namedConstraints.RUnlock()
+	return match, ok
+}
+
+// compileConstraints strips the constraints out of pattern. It returns the
+// pattern in plain :param form and the constraint of each parameter in
+// order (nil when the parameter has none, or when no parameter has one). A
+// constraint or braces end the parameter name, so static text may follow in
+// the segment (see paramEnd).
+//
+// Supported forms:
+//
+//	:id<int>      named constraint (see RegisterConstraint)
+//	{id}          parameter without constraint
+//	{id:[0-9]+}   regular expression matching the whole value
+func compileConstraints(pattern string) (string, []func(string) bool, error) {
+	var (
+		b           strings.Builder
+		constraints []func(string) bool
+		constrained bool
+	)
+	b.Grow(len(pattern))
+
+	for i := 0; i < len(pattern); {
+		switch pattern[i] {
+		case ':':
+			// Parameter name runs until the segment or the constraint starts
+			j := i + 1
+			for j < len(pattern) && !isParamNameEnd(pattern[j]) && pattern[j] != '<' {
+				j++
+			}
+			b.WriteString(pattern[i:j])
+
+			var match func(string) bool
+			if j < len(pattern) && pattern[j] == '<' {
+				end := strings.IndexByte(pattern[j:], '>')
+				if end == -1 {
+					return "", nil, fmt.Errorf("unterminated parameter constraint")
+				}
+				name := pattern[j+1 : j+end]
+				if name == "" {
+					return "", nil, fmt.Errorf("parameter constraint cannot be empty")
+				}
+				var ok bool
+				if match, ok = lookupConstraint(name); !ok {
+					return "", nil, fmt.Errorf("unknown parameter constraint %q", name)
+				}
+				constrained = true
+				j += end + 1
+				if j < len(pattern) && pattern[j] == '?' {
+					match = optionalConstraint(match)
+				}
+				endParamName(&b, pattern, j)
+			}
+			constraints = append(constraints, match)
+			i = j
+
+		case '{':
+			// Find the matching brace (regular expressions may contain braces)
+			depth, j := 0, i
+			for ; j < len(pattern); j++ {
+				if pattern[j] == '{' {
+					depth++
+				} else if pattern[j] == '}' {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+			}
+			if j == len(pattern) {
+				return "", nil, fmt.Errorf("unterminated parameter")
+			}
+
+			name, expr, hasExpr := strings.Cut(pattern[i+1:j], ":")
+			if name == "" {
+				return "", nil, fmt.Errorf("parameter name cannot be empty")
+			}
+			b.WriteByte(':')
+			b.WriteString(name)
+
+			var match func(string) bool
+			if hasExpr {
+				if expr == "" {
+					return "", nil, fmt.Errorf("parameter constraint cannot be empty")
+				}
+				re, err := regexp.Compile("^(?:" + expr + ")$")
+				if err != nil {
+					return "", nil, fmt.Errorf("invalid parameter constraint %q: %v", expr, err)
+				}
+				match = re.MatchString
+				constrained = true
+			}
+			i = j + 1
+			if match != nil && i < len(pattern) && pattern[i] == '?' {
+				match = optionalConstraint(match)
+			}
+			endParamName(&b, pattern, i)
+			constraints = append(constraints, match)
+
+		case '}':
+			return "", nil, fmt.Errorf("unexpected '}' in pattern")
+
+		default:
+			b.WriteByte(pattern[i])
+			i++
+		}
+	}
+
+	if !constrained {
+		constraints = nil
+	}
+	return b.String(), constraints, nil
+}
+
+// Let the empty value of an omitted optional parameter pass its constraint
+func optionalConstraint(match func(string) bool) func(string) bool {
+	return func(v string) bool {
+		return v == "" || match(v)
+	}
+}
+
+// Mark the end of the parameter name written to b when the text of pattern
+// at i would continue the name
+func endParamName(b *strings.Builder, pattern string, i int) {
+	if i < len(pattern) && isValidParamChar(pattern[i]) {
+		b.WriteByte(paramEnd)
+	}
+}
+
+// Check parameter values against their constraints
+func checkConstraints(constraints []func(string) bool, values []string) bool {
+	for i, value := range values {
+		if i < len(constraints) && constraints[i] != nil && !constraints[i](value) {
+			return false
+		}
+	}
+	return true
+}
+
+func isInt(v string) bool {
+	if len(v) > 1 && v[0] == '-' {
+		v = v[1:]
+	}
+	return isUint(v)
+}
+
+func isUint(v string) bool {
+	if v == "" {
+		return false
+	}
+	for i := 0; i < len(v); i++ {
+		if v[i] < '0' || v[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlpha(v string) bool {
+	if v == "" {
+		return false
+	}
+	for i := 0; i < len(v); i++ {
+		if !isASCIILetter(v[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlnum(v string) bool {
+	if v == "" {
+		return false
+	}
+	for i := 0; i < len(v); i++ {
+		if !isASCIILetter(v[i]) && (v[i] < '0' || v[i] > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(v string) bool {
+	if v == "" {
+		return false
+	}
+	for i := 0; i < len(v); i++ {
+		if !isHexDigit(v[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isUUID checks the canonical 8-4-4-4-12 form
+func isUUID(v string) bool {
+	if len(v) != 36 {
+		return false
+	}
+	for i := 0; i < len(v); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if v[i] != '-' {
+				return false
+			}
+		default:
+			if !isHexDigit(v[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isASCIILetter(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isHexDigit(ch byte) bool {
+	return (ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
@@ -0,0 +1,142 @@
+package bon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Test typed and regex-constrained parameters
+func TestMuxParamConstraints(t *testing.T) {
+	r := NewRouter()
+
+	r.Get("/users/:id<int>", orderHandler("int"))
+	r.Get("/users/:id<uuid>", orderHandler("uuid"))
+	r.Get("/users/:id", orderHandler("any"))
+	r.Get("/orders/{id:[a-z]{2}[0-9]+}", orderHandler("regex"))
+	r.Get("/orders/{id}", orderHandler("brace"))
+	r.Get("/items/:id<uint>/detail", orderHandler("item"))
+
+	if err := Verify(r, []*Want{
+		{"/users/123", 200, "int"},
+		{"/users/-7", 200, "int"},
+		{"/users/0b3c6f0e-4f2a-4c1e-9d8a-1a2b3c4d5e6f", 200, "uuid"},
+		{"/users/me-settings", 200, "any"},
+		{"/orders/ab123", 200, "regex"},
+		{"/orders/ab123x", 200, "brace"},
+		{"/items/42/detail", 200, "item"},
+		{"/items/-1/detail", 404, BodyNotFound},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test custom named constraints
+func TestRegisterConstraint(t *testing.T) {
+	RegisterConstraint("lower", func(v string) bool {
+		return v != "" && strings.ToLower(v) == v
+	})
+
+	r := NewRouter()
+	r.Get("/tags/:tag<lower>", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(URLParam(req, "tag")))
+	})
+
+	if err := Verify(r, []*Want{
+		{"/tags/golang", 200, "golang"},
+		{"/tags/GoLang", 404, BodyNotFound},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test malformed constraints are rejected at registration
+func TestMuxParamConstraintValidation(t *testing.T) {
+	tests := []struct {
+		pattern string
+		valid   bool
+	}{
+		{"/users/:id<int>", true},
+		{"/users/:id<int>/posts", true},
+		{"/users/{id}", true},
+		{"/users/{id:[0-9]{1,3}}", true},
+		{"/users/:id<unknown>", false},
+		{"/users/:id<>", false},
+		{"/users/:id<int", false},
+		{"/users/:id<int>x", true},
+		{"/users/:id<int>:name", false},
+		{"/users/:<int>", false},
+		{"/users/{id:[0-9+}", false},
+		{"/users/{id:}", false},
+		{"/users/{}", false},
+		{"/users/{:[0-9]+}", false},
+		{"/users/{id", false},
+		{"/users/id}", false},
+		{"/users/{id}x", true},
+		{"/users/{id}{name}", false},
+	}
+
+	for _, tt := range tests {
+		err := validatePattern(tt.pattern)
+		if tt.valid && err != nil {
+			t.Errorf("Pattern %q should be valid, got %v", tt.pattern, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("Pattern %q should be invalid", tt.pattern)
+		}
+	}
+}
+
+func TestBuiltinConstraints(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{"int", "42", true},
+		{"int", "-42", true},
+		{"int", "-", false},
+		{"int", "4a", false},
+		{"uint", "42", true},
+		{"uint", "-42", false},
+		{"alpha", "abcXYZ", true},
+		{"alpha", "abc1", false},
+		{"alnum", "abc1", true},
+		{"alnum", "abc-1", false},
+		{"hex", "deadBEEF", true},
+		{"hex", "xyz", false},
+		{"uuid", "0b3c6f0e-4f2a-4c1e-9d8a-1a2b3c4d5e6f", true},
+		{"uuid", "0b3c6f0e4f2a4c1e9d8a1a2b3c4d5e6f", false},
+	}
+
+	for _, tt := range tests {
+		match, ok := lookupConstraint(tt.name)
+		if !ok {
+			t.Fatalf("Constraint %q not registered", tt.name)
+		}
+		if got := match(tt.value); got != tt.want {
+			t.Errorf("%s(%q) = %v, want %v", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+// Test constraint failures fall through to 405 detection as well
+func TestMuxParamConstraintMethodNotAllowed(t *testing.T) {
+	r := NewRouter()
+	r.Delete("/users/:id<int>", func(w http.ResponseWriter, req *http.Request) {})
+
+	req := httptest.NewRequest("GET", "/users/abc", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 when constraint fails, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/users/1", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 when constraint matches, got %d", w.Code)
+	}
+}
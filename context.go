@@ -3,6 +3,8 @@ package bon
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"sync"
 )
 
 var contextKey = &struct {
@@ -13,7 +15,10 @@ var contextKey = &struct {
 
 type (
 	Context struct {
-		params params
+		params  params
+		mux     *Mux      // Mux serving the request (see Forward)
+		route   *endpoint // Matched route
+		pattern string    // Request pattern of the matched route (see RoutePattern)
 	}
 
 	params struct {
@@ -34,9 +39,22 @@ func (ctx *Context) reset() *Context {
 	// Just reset lengths - no need to clear strings
 	ctx.params.keys = ctx.params.keys[:0]
 	ctx.params.values = ctx.params.values[:0]
+	ctx.mux = nil
+	ctx.route = nil
+	ctx.pattern = ""
 	return ctx
 }
 
+// setPathValues makes the parameters available to r.PathValue, except the
+// value of an unnamed wildcard
+func (ctx *Context) setPathValues(r *http.Request) {
+	for i, key := range ctx.params.keys {
+		if key != WildcardKey {
+			r.SetPathValue(key, ctx.params.values[i])
+		}
+	}
+}
+
 func (ctx *Context) PutParam(key, value string) {
 	ctx.params.keys = append(ctx.params.keys, key)
 	ctx.params.values = append(ctx.params.values, value)
@@ -71,11 +89,106 @@ func (ctx *Context) GetParam(key string) string {
 	}
 }
 
+// WildcardKey is the URLParam key of the value captured by an unnamed
+// trailing wildcard (e.g., "/files/*")
+const WildcardKey = "*"
+
+// ContextHandlerFunc is a handler receiving the parameters of the matched
+// route as ctx. Register it with Handle:
+//
+//	r.Handle(http.MethodGet, "/users/:id", bon.ContextHandlerFunc(
+//		func(w http.ResponseWriter, r *http.Request, ctx *bon.Context) {
+//			w.Write([]byte(ctx.GetParam("id")))
+//		}))
+//
+// The Mux passes its pooled Context to the function without storing it in
+// the request context, so that routes with parameters are served without
+// allocating, also through middleware passing the request on unchanged,
+// which reads the parameters with URLParam. A middleware passing a copy made
+// with r.WithContext still has the parameters reach the function. Other
+// copies, such as with r.Clone or a new URL, leave the function without
+// parameters: register a plain handler with such middleware. ctx is reused
+// once the function returns and must not be retained.
+type ContextHandlerFunc func(w http.ResponseWriter, r *http.Request, ctx *Context)
+
+// ServeHTTP calls f with the Context the Mux holds for r, or else the one
+// stored in the request context
+func (f ContextHandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := contextSlots.get(r, true)
+	if !ok {
+		ctx, ok = requestContext(r)
+	}
+	if !ok {
+		ctx = &Context{}
+	}
+	f(w, r, ctx)
+}
+
+// contextSlots holds the Context of the requests a Mux passes through the
+// middleware of a ContextHandlerFunc route, instead of storing it in the
+// request context (see Mux.serveContextHandler). Maps reuse the space of
+// removed entries, so filling a slot does not allocate.
+var contextSlots = &requestSlots{m: make(map[*url.URL]requestSlot)}
+
+type (
+	// requestSlots holds the Context of requests by their URL, which the
+	// copies made with http.Request.WithContext share
+	requestSlots struct {
+		mu sync.Mutex
+		m  map[*url.URL]requestSlot
+	}
+
+	// requestSlot is the Context held for a request
+	requestSlot struct {
+		r   *http.Request
+		ctx *Context
+	}
+)
+
+// put holds ctx for r and returns the slot it replaces
+func (s *requestSlots) put(r *http.Request, ctx *Context) requestSlot {
+	s.mu.Lock()
+	prev := s.m[r.URL]
+	s.m[r.URL] = requestSlot{r: r, ctx: ctx}
+	s.mu.Unlock()
+	return prev
+}
+
+// restore puts back the slot replaced by put for r
+func (s *requestSlots) restore(r *http.Request, prev requestSlot) {
+	s.mu.Lock()
+	if prev.ctx != nil {
+		s.m[r.URL] = prev
+	} else {
+		delete(s.m, r.URL)
+	}
+	s.mu.Unlock()
+}
+
+// get returns the Context held for r, or for a copy of r sharing its URL
+// unless same is true
+func (s *requestSlots) get(r *http.Request, same bool) (*Context, bool) {
+	s.mu.Lock()
+	slot, ok := s.m[r.URL]
+	s.mu.Unlock()
+	if !ok || (same && slot.r != r) {
+		return nil, false
+	}
+	return slot.ctx, true
+}
+
+// requestContext returns the Context of the Mux routing r, stored in the
+// request context or held for a ContextHandlerFunc route
+func requestContext(r *http.Request) (*Context, bool) {
+	if ctx, ok := r.Context().Value(contextKey).(*Context); ok {
+		return ctx, true
+	}
+	return contextSlots.get(r, false)
+}
+
 func URLParam(r *http.Request, key string) string {
-	if v := r.Context().Value(contextKey); v != nil {
-		if ctx, ok := v.(*Context); ok {
-			return ctx.GetParam(key)
-		}
+	if ctx, ok := requestContext(r); ok {
+		return ctx.GetParam(key)
 	}
 	return ""
 }
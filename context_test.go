@@ -3,6 +3,7 @@ package bon
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -74,3 +75,93 @@ func TestContext(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestContextHandlerFunc(t *testing.T) {
+	r := NewRouter()
+	params := func(keys ...string) ContextHandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, ctx *Context) {
+			for _, key := range keys {
+				_, _ = w.Write([]byte(ctx.GetParam(key) + ";"))
+			}
+		}
+	}
+
+	r.Handle("GET", "/static", params("id"))
+	r.Handle("GET", "/users/:id", params("id"))
+	// Middleware copying the request with WithContext still passes the parameters
+	r.Handle("GET", "/posts/:id", params("id"), ContextMiddleware(TestContextKeyAAA, &ContextValue{value: "AAA"}))
+
+	sub := NewRouter()
+	sub.Handle("GET", "/", params("tenant"))
+	sub.Handle("GET", "/items/:item", params("tenant", "item"))
+	r.Mount("/tenants/:tenant", sub)
+
+	r.Host("{sub}.example.com").Handle("GET", "/:page", params("sub", "page"))
+
+	if err := Verify(r,
+		[]*Want{
+			{"/static", 200, ";"},
+			{"/users/1", 200, "1;"},
+			{"/posts/2", 200, "2;"},
+			{"/tenants/acme/", 200, "acme;"},
+			{"/tenants/acme/items/3", 200, "acme;3;"},
+		},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "http://docs.example.com/intro", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if got := w.Body.String(); got != "docs;intro;" {
+		t.Errorf("Expected host parameters, got %q", got)
+	}
+}
+
+func TestMuxPathValues(t *testing.T) {
+	r := NewRouter()
+	r.PathValues = true
+	values := func(keys ...string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			for _, key := range keys {
+				_, _ = w.Write([]byte(r.PathValue(key) + ";"))
+			}
+		}
+	}
+
+	r.Get("/users/:id<int>", values("id"))
+	r.Get("/files/:name.:ext", values("name", "ext"))
+	r.Get("/docs/*path", values("path"))
+	r.Get("/assets/*", values("*"))
+	r.Get("/posts/:id", values("id"), ContextMiddleware(TestContextKeyAAA, &ContextValue{value: "AAA"}))
+	r.Handle("GET", "/items/:item", ContextHandlerFunc(func(w http.ResponseWriter, r *http.Request, ctx *Context) {
+		_, _ = w.Write([]byte(r.PathValue("item") + ";"))
+	}))
+
+	// Parameters of the Mux mounted from are passed down
+	sub := NewRouter()
+	sub.Get("/", values("tenant"))
+	r.Mount("/tenants/:tenant", sub)
+
+	other := NewRouter()
+	other.Get("/users/:id", values("id"))
+
+	if err := Verify(r,
+		[]*Want{
+			{"/users/1", 200, "1;"},
+			{"/files/a.txt", 200, "a;txt;"},
+			{"/docs/a/b", 200, "a/b;"},
+			{"/assets/a/b", 200, ";"},
+			{"/posts/2", 200, "2;"},
+			{"/items/3", 200, "3;"},
+			{"/tenants/acme/", 200, "acme;"},
+		},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// Path values are not set by default
+	if err := Verify(other, []*Want{{"/users/1", 200, ";"}}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,67 @@
+package bon
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Route registration errors, wrapped in *RouteError
+var (
+	// ErrInvalidRoute is returned for an invalid method or pattern
+	ErrInvalidRoute = errors.New("invalid route")
+	// ErrDuplicateRoute is returned for a route with the method, pattern and
+	// request matchers, or the name, of a registered route
+	ErrDuplicateRoute = errors.New("duplicate route")
+	// ErrShadowedRoute is returned for a route that matches the same requests
+	// as a registered route differing only in parameter names, so that the
+	// registered route always wins
+	ErrShadowedRoute = errors.New("shadowed route")
+)
+
+// RouteError describes a route that could not be registered. Use errors.Is
+// with ErrInvalidRoute, ErrDuplicateRoute or ErrShadowedRoute to tell the
+// problems apart.
+type RouteError struct {
+	Method   string // Method of the route ("" when not known)
+	Pattern  string // Pattern with the group or route prefix
+	Conflict string // Registered route conflicting with the route ("METHOD /path")
+	Err      error
+}
+
+func (e *RouteError) Error() string {
+	msg := "bon: " + e.Pattern + ": " + e.Err.Error()
+	if e.Method != "" {
+		msg = "bon: " + e.Method + " " + e.Pattern + ": " + e.Err.Error()
+	}
+	if e.Conflict != "" {
+		msg += " (conflicts with " + e.Conflict + ")"
+	}
+	return msg
+}
+
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
+
+// Create error for an invalid route
+func invalidRouteError(method, pattern string, err error) *RouteError {
+	return &RouteError{
+		Method:  method,
+		Pattern: pattern,
+		Err:     fmt.Errorf("%w: %v", ErrInvalidRoute, err),
+	}
+}
+
+// Create error for ep conflicting with the registered route other
+func conflictError(ep, other *endpoint, err error) *RouteError {
+	conflict := other.method + " " + other.pattern
+	if other.host != "" {
+		conflict = other.method + " " + other.host + other.pattern
+	}
+	return &RouteError{
+		Method:   ep.method,
+		Pattern:  ep.pattern,
+		Conflict: conflict,
+		Err:      err,
+	}
+}
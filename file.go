@@ -9,7 +9,6 @@ import (
 
 type fileServer struct {
 	mux      *Mux
-	depth    int
 	root     string
 	absRoot  string // Cache absolute path
 	dirIndex string
@@ -23,7 +22,7 @@ func contentsHandle(r Router, pattern string, handlerFunc http.HandlerFunc, midd
 	}
 }
 
-func (m *Mux) newFileServer(pattern, root string) *fileServer {
+func (m *Mux) newFileServer(root string) *fileServer {
 	// Pre-calculate and cache absolute path
 	absRoot, err := filepath.Abs(root)
 	if err != nil {
@@ -35,24 +34,13 @@ func (m *Mux) newFileServer(pattern, root string) *fileServer {
 		mux:      m,
 		root:     root,
 		absRoot:  absRoot,
-		depth:    strings.Count(resolvePattern(pattern), "/"),
 		dirIndex: "index.html",
 	}
 }
 
-func (fs *fileServer) resolveFilePath(v string) (string, error) {
-	var s, i int
-	for ; i < len(v); i++ {
-		if v[i] == '/' {
-			s++
-			if fs.depth == s {
-				break
-			}
-		}
-	}
-
+// resolveFilePath resolves the path captured by the wildcard under root
+func (fs *fileServer) resolveFilePath(requestPath string) (string, error) {
 	// Normalize path - remove leading slash
-	requestPath := v[i:]
 	if requestPath != "" && requestPath[0] == '/' {
 		requestPath = requestPath[1:]
 	}
@@ -91,7 +79,7 @@ func (fs *fileServer) resolveFilePath(v string) (string, error) {
 }
 
 func (fs *fileServer) contents(w http.ResponseWriter, r *http.Request) {
-	file, err := fs.resolveFilePath(r.URL.Path)
+	file, err := fs.resolveFilePath(URLParam(r, WildcardKey))
 	if err != nil {
 		if err == os.ErrPermission {
 			http.Error(w, "Forbidden", http.StatusForbidden)
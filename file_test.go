@@ -0,0 +1,61 @@
+package bon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Test file server resolves files from the wildcard value
+func TestFileServer(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "css"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"index.html":   "index",
+		"css/app.css":  "body{}",
+		".secret":      "secret",
+		"css/a b.css":  "space",
+		"css/index.md": "md",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewRouter()
+	r.FileServer("/static", root)
+	r.Group("/api").Group("/v1").FileServer("/assets", root)
+	r.Route().FileServer("/public", root)
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"/static/", http.StatusOK, "index"},
+		{"/static/css/app.css", http.StatusOK, "body{}"},
+		{"/static/css/a%20b.css", http.StatusOK, "space"},
+		{"/static/.secret", http.StatusForbidden, "Forbidden\n"},
+		{"/static/missing.txt", http.StatusNotFound, "404 page not found\n"},
+		{"/api/v1/assets/css/app.css", http.StatusOK, "body{}"},
+		{"/public/index.html", http.StatusOK, "index"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.wantCode, w.Code)
+		}
+		if w.Body.String() != tt.wantBody {
+			t.Errorf("%s: expected body %q, got %q", tt.path, tt.wantBody, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package bon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Maximum number of times a request can be forwarded
+const maxForwardDepth = 10
+
+// Forward errors
+var (
+	// ErrForwardUnavailable is returned by Forward for a request that has no
+	// Context of a Mux (see Mux.Forward)
+	ErrForwardUnavailable = errors.New("bon: request was not routed with a Context")
+	// ErrForwardLoop is returned by Forward for a request forwarded to a
+	// method and path it was already forwarded from or to, or forwarded too
+	// many times
+	ErrForwardLoop = errors.New("bon: forward loop")
+)
+
+var forwardKey = &struct {
+	name string
+}{
+	name: "BON_FORWARD",
+}
+
+// forwarded records a forward of a request
+type forwarded struct {
+	from   MatchedRoute // Route that forwarded the request
+	method string       // Method and path forwarded from
+	path   string
+	target string // Method and path forwarded to ("METHOD /path")
+	depth  int
+	prev   *forwarded
+}
+
+// Forward serves r as a request for method and path with the routes of the
+// Mux that routed it, as if the client had requested them:
+//
+//	r.Get("/legacy/users/:id", func(w http.ResponseWriter, req *http.Request) {
+//		_ = bon.Forward(w, req, http.MethodGet, "/users/"+bon.URLParam(req, "id"))
+//	})
+//
+// The request must have been routed with a Context, which the Mux stores for
+// routes with parameters, a name or metadata, and in mounted Muxes. Routes
+// without parameters are served without one to avoid allocations and
+// Forward returns ErrForwardUnavailable for them; their handlers forward
+// with Mux.Forward instead.
+func Forward(w http.ResponseWriter, r *http.Request, method, path string) error {
+	ctx, ok := r.Context().Value(contextKey).(*Context)
+	if !ok || ctx.mux == nil {
+		return ErrForwardUnavailable
+	}
+	return ctx.mux.Forward(w, r, method, path)
+}
+
+// Forward serves r as a request for method and path with the routes of m, or
+// of the Mux m is mounted on, as if the client had requested them. It works
+// from any route, with or without a Context:
+//
+//	r.Get("/me", func(w http.ResponseWriter, req *http.Request) {
+//		_ = r.Forward(w, req, "", "/users/"+currentUserID(req))
+//	})
+//
+// The path is the full path, also from a mounted Mux, and may have a query
+// replacing that of r. An empty method keeps the method of r. The route
+// matched for the path gets its own parameters, and OriginalRoute reports the
+// route matched first. A request forwarded to a method and path it already
+// went through, or forwarded more than 10 times, is not served and
+// ErrForwardLoop is returned.
+func (m *Mux) Forward(w http.ResponseWriter, r *http.Request, method, path string) error {
+	for m.parent != nil {
+		m = m.parent
+	}
+
+	if method == "" {
+		method = r.Method
+	}
+	path, query, hasQuery := strings.Cut(path, "?")
+	if !hasQuery {
+		query = r.URL.RawQuery
+	}
+
+	from, _ := RouteInfo(r)
+	fw := &forwarded{
+		from:   from,
+		method: r.Method,
+		path:   r.URL.Path,
+		target: method + " " + path,
+		depth:  1,
+	}
+	if prev, ok := r.Context().Value(forwardKey).(*forwarded); ok {
+		fw.prev = prev
+		fw.depth = prev.depth + 1
+	}
+	if fw.depth > maxForwardDepth {
+		return fmt.Errorf("%w: %s forwarded more than %d times", ErrForwardLoop, fw.target, maxForwardDepth)
+	}
+	for p := fw; p != nil; p = p.prev {
+		if (p.method == method && p.path == path) || (p != fw && p.target == fw.target) {
+			return fmt.Errorf("%w: %s", ErrForwardLoop, fw.target)
+		}
+	}
+
+	// Hide the parameters of the forwarding route, keeping the Mux for
+	// further forwards from routes served without a Context
+	c := context.WithValue(r.Context(), forwardKey, fw)
+	c = context.WithValue(c, contextKey, &Context{mux: m})
+	r2 := r.Clone(c)
+	if ctx, ok := r.Context().Value(contextKey).(*Context); ok {
+		for _, key := range ctx.params.keys {
+			if key != WildcardKey && r2.PathValue(key) != "" {
+				r2.SetPathValue(key, "")
+			}
+		}
+	}
+	r2.Method = method
+	r2.URL.Path = path
+	r2.URL.RawPath = ""
+	r2.URL.RawQuery = query
+	r2.Pattern = ""
+
+	m.ServeHTTP(w, r2)
+	return nil
+}
+
+// OriginalRoute describes the route that matched a forwarded request before
+// it was first forwarded, and reports whether r was forwarded (see Forward).
+func OriginalRoute(r *http.Request) (MatchedRoute, bool) {
+	fw, ok := r.Context().Value(forwardKey).(*forwarded)
+	if !ok {
+		return MatchedRoute{}, false
+	}
+	for fw.prev != nil {
+		fw = fw.prev
+	}
+	return fw.from, true
+}
@@ -0,0 +1,124 @@
+package bon
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test forwarding requests to other routes
+func TestForward(t *testing.T) {
+	r := NewRouter()
+	r.PathValues = true
+
+	r.Get("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		original, _ := OriginalRoute(req)
+		_, _ = w.Write([]byte("user " + URLParam(req, "id") + " name=" + URLParam(req, "name") + " legacy=" + req.PathValue("name") +
+			" q=" + req.URL.RawQuery + " route=" + RoutePattern(req) + " from=" + original.Pattern))
+	})
+	r.Post("/users", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("create " + req.Method))
+	})
+	r.Get("/legacy/:name", func(w http.ResponseWriter, req *http.Request) {
+		if err := Forward(w, req, "", "/users/"+URLParam(req, "name")); err != nil {
+			t.Error(err)
+		}
+		// The forwarding request is unchanged
+		_, _ = w.Write([]byte(" then " + RoutePattern(req) + " " + URLParam(req, "name")))
+	})
+	// Routes without parameters forward through the Mux
+	r.Get("/me", func(w http.ResponseWriter, req *http.Request) {
+		_ = r.Forward(w, req, "", "/users/1?fields=name")
+	})
+	r.Get("/signup", func(w http.ResponseWriter, req *http.Request) {
+		_ = r.Forward(w, req, http.MethodPost, "/users")
+	})
+	// Forwarded twice
+	r.Get("/alias", func(w http.ResponseWriter, req *http.Request) {
+		_ = r.Forward(w, req, "", "/legacy/7")
+	})
+
+	sub := NewRouter()
+	sub.Get("/profile", func(w http.ResponseWriter, req *http.Request) {
+		_ = Forward(w, req, "", "/users/"+URLParam(req, "tenant"))
+	})
+	r.Mount("/tenants/:tenant", sub)
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"/legacy/42?x=1", "user 42 name= legacy= q=x=1 route=/users/:id from=/legacy/:name then /legacy/:name 42"},
+		{"/me?x=1", "user 1 name= legacy= q=fields=name route=/users/:id from=/me"},
+		{"/signup", "create POST"},
+		{"/alias", "user 7 name= legacy= q= route=/users/:id from=/alias then /legacy/:name 7"},
+		{"/tenants/acme/profile", "user acme name= legacy= q= route=/users/:id from=/tenants/:tenant/profile"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+		if got := w.Body.String(); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.target, tt.want, got)
+		}
+	}
+}
+
+// Test loop detection and requests that cannot be forwarded
+func TestForwardErrors(t *testing.T) {
+	var errs []error
+	forward := func(path string) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			errs = append(errs, Forward(w, req, "", path))
+		}
+	}
+
+	r := NewRouter()
+	r.Get("/self/:id", forward("/self/1"))
+	r.Get("/a/:id", forward("/b/1"))
+	r.Get("/b/:id", forward("/a/1"))
+	r.Get("/chain/:n<int>", func(w http.ResponseWriter, req *http.Request) {
+		n := URLParam(req, "n")
+		errs = append(errs, Forward(w, req, "", "/chain/"+n+"0"))
+	})
+	r.Get("/static", forward("/self/1"))
+	r.Get("/loop", func(w http.ResponseWriter, req *http.Request) {
+		errs = append(errs, r.Forward(w, req, "", "/static/loop"))
+	})
+	r.Get("/static/loop", func(w http.ResponseWriter, req *http.Request) {
+		errs = append(errs, r.Forward(w, req, "", "/loop"))
+	})
+
+	tests := []struct {
+		target string
+		want   error
+	}{
+		{"/self/1", ErrForwardLoop},
+		{"/a/1", ErrForwardLoop},
+		{"/chain/1", ErrForwardLoop},
+		{"/loop", ErrForwardLoop},
+		// Routes without parameters have no Context for the package-level Forward
+		{"/static", ErrForwardUnavailable},
+	}
+
+	for _, tt := range tests {
+		errs = nil
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", tt.target, nil))
+		// The innermost forward fails first
+		if len(errs) == 0 || !errors.Is(errs[0], tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.target, tt.want, errs)
+		}
+	}
+
+	// Depth limit
+	errs = nil
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/chain/1", nil))
+	if len(errs) != maxForwardDepth+1 {
+		t.Errorf("Expected %d forwards, got %d", maxForwardDepth+1, len(errs))
+	}
+
+	if err := Forward(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), "", "/a/1"); !errors.Is(err, ErrForwardUnavailable) {
+		t.Errorf("Expected unavailable forward, got %v", err)
+	}
+}
@@ -9,6 +9,7 @@ type Group struct {
 	mux         *Mux
 	middlewares []Middleware
 	prefix      string
+	options     []RouteOption
 }
 
 func (g *Group) Group(pattern string, middlewares ...Middleware) *Group {
@@ -16,6 +17,17 @@ func (g *Group) Group(pattern string, middlewares ...Middleware) *Group {
 		mux:         g.mux,
 		middlewares: append(g.middlewares, middlewares...),
 		prefix:      g.prefix + resolvePatternPrefix(pattern),
+		options:     g.options,
+	}
+}
+
+// With returns a copy of the group applying options to the routes registered through it
+func (g *Group) With(options ...RouteOption) *Group {
+	return &Group{
+		mux:         g.mux,
+		middlewares: append([]Middleware{}, g.middlewares...),
+		prefix:      g.prefix,
+		options:     append(append([]RouteOption{}, g.options...), options...),
 	}
 }
 
@@ -24,6 +36,7 @@ func (g *Group) Route(middlewares ...Middleware) *Route {
 		mux:         g.mux,
 		middlewares: middlewares,
 		prefix:      g.prefix,
+		options:     g.options,
 	}
 }
 
@@ -68,20 +81,47 @@ func (g *Group) Trace(pattern string, handlerFunc http.HandlerFunc, middlewares
 }
 
 func (g *Group) Handle(method, pattern string, handler http.Handler, middlewares ...Middleware) {
-	// Safely combine prefix and pattern
+	g.mux.handle(method, g.fullPattern(pattern), handler, append(g.middlewares, middlewares...), g.options)
+}
+
+// TryHandle registers a route like Handle, but returns an error instead of
+// panicking or replacing the route (see Mux.TryHandle)
+func (g *Group) TryHandle(method, pattern string, handler http.Handler, middlewares ...Middleware) error {
+	return g.mux.tryHandle(method, g.fullPattern(pattern), handler, append(g.middlewares, middlewares...), g.options, true)
+}
+
+// Remove unregisters the routes registered through the group with method
+// and pattern, including the host of a group returned by Host (see
+// Mux.Remove)
+func (g *Group) Remove(method, pattern string) bool {
+	return g.mux.remove(optionsHost(g.options), method, g.fullPattern(pattern))
+}
+
+// Combine group prefix and pattern
+func (g *Group) fullPattern(pattern string) string {
 	fullPattern := g.prefix + resolvePatternPrefix(pattern)
 	// Remove consecutive slashes
 	for strings.Contains(fullPattern, "//") {
 		fullPattern = strings.ReplaceAll(fullPattern, "//", "/")
 	}
-	g.mux.Handle(method, fullPattern, handler, append(g.middlewares, middlewares...)...)
+	return fullPattern
 }
 
 func (g *Group) FileServer(pattern, root string, middlewares ...Middleware) {
-	p := g.prefix + resolvePatternPrefix(pattern)
-	// Remove consecutive slashes
-	for strings.Contains(p, "//") {
-		p = strings.ReplaceAll(p, "//", "/")
+	// The group prefix is added by Handle
+	contentsHandle(g, pattern, g.mux.newFileServer(root).contents, middlewares...)
+}
+
+// Mount dispatches requests for any method under prefix to handler (see Mux.Mount)
+func (g *Group) Mount(prefix string, handler http.Handler, middlewares ...Middleware) {
+	mountHandle(g, prefix, g.mux.newMount(g.prefix+resolvePatternPrefix(prefix), handler), middlewares...)
+}
+
+// Redirect registers a redirect route under the group prefix (see
+// Mux.Redirect). The target is not prefixed.
+func (g *Group) Redirect(from, to string, code int, options ...RedirectOption) {
+	rr := newRedirectRoute(g.fullPattern(from), to, code, options)
+	for _, method := range redirectMethods(code) {
+		g.Handle(method, from, rr)
 	}
-	contentsHandle(g, p, g.mux.newFileServer(p, root).contents, middlewares...)
 }
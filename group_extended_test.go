@@ -259,7 +259,7 @@ func TestGroupErrorCases(t *testing.T) {
 		{"POST:/api/resource", 200, "POST"},
 		{"/api/existing", 200, "EXISTS"},
 		{"/api/nonexistent", 404, "404 page not found\n"},
-		{"DELETE:/api/resource", 404, "404 page not found\n"},
+		{"DELETE:/api/resource", 405, "Method Not Allowed\n"},
 	}); err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,71 @@
+package bon
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// headResponseWriter discards the body written by a GET route answering a
+// HEAD request. The status line is delayed until the handler returns so that
+// Content-Length can report the size of the discarded body.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status    int
+	size      int64
+	committed bool
+}
+
+func (hw *headResponseWriter) WriteHeader(code int) {
+	// Informational responses are sent immediately
+	if code >= 100 && code < 200 {
+		hw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if hw.status == 0 {
+		hw.status = code
+	}
+}
+
+func (hw *headResponseWriter) Write(b []byte) (int, error) {
+	if hw.status == 0 {
+		hw.status = http.StatusOK
+	}
+	hw.size += int64(len(b))
+	return len(b), nil
+}
+
+// Flush commits the headers; the Content-Length is unknown from this point
+func (hw *headResponseWriter) Flush() {
+	hw.commit()
+	if f, ok := hw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter
+// This allows http.ResponseController to work correctly in Go 1.20+
+func (hw *headResponseWriter) Unwrap() http.ResponseWriter {
+	return hw.ResponseWriter
+}
+
+// commit writes the delayed status line once
+func (hw *headResponseWriter) commit() {
+	if hw.committed {
+		return
+	}
+	hw.committed = true
+
+	if hw.status == 0 {
+		hw.status = http.StatusOK
+	}
+
+	// Report the size of the discarded body unless the handler already did
+	if hw.status != http.StatusNoContent && hw.status != http.StatusNotModified {
+		h := hw.ResponseWriter.Header()
+		if h.Get("Content-Length") == "" && h.Get("Transfer-Encoding") == "" {
+			h.Set("Content-Length", strconv.FormatInt(hw.size, 10))
+		}
+	}
+
+	hw.ResponseWriter.WriteHeader(hw.status)
+}
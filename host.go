@@ -0,0 +1,213 @@
+package bon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// hostTable holds the routes of a host pattern
+type hostTable struct {
+	pattern string    // Normalized host pattern (e.g., "{tenant}.example.com")
+	labels  []string  // Pattern labels ("" for parameters)
+	keys    []string  // Parameter names in label order
+	data    *trieData // Routes of the host
+}
+
+// Host returns a group whose routes only match requests for hosts matching
+// pattern. A label of the pattern can be a parameter written as {name},
+// readable through URLParam:
+//
+//	tenant := r.Host("{tenant}.example.com")
+//	tenant.Get("/", func(w http.ResponseWriter, r *http.Request) {
+//		w.Write([]byte(bon.URLParam(r, "tenant")))
+//	})
+//
+// Hosts are matched case-insensitively without the port. Hosts without
+// parameters take priority over hosts with parameters, and among those the
+// pattern with more static labels wins. Requests for hosts without a matching
+// pattern are routed with the routes registered directly on the Mux.
+// Requests for a matching host are routed with the routes of the host only.
+func (m *Mux) Host(pattern string) *Group {
+	table, err := parseHostPattern(pattern)
+	if err != nil {
+		panic("bon: " + err.Error())
+	}
+	return &Group{
+		mux:     m,
+		options: []RouteOption{withHost(table.pattern)},
+	}
+}
+
+// Route option restricting the route to the host pattern
+func withHost(pattern string) RouteOption {
+	return func(ep *endpoint) {
+		ep.host = pattern
+	}
+}
+
+// Host pattern set by options ("" for none)
+func optionsHost(options []RouteOption) string {
+	ep := &endpoint{}
+	for _, option := range options {
+		option(ep)
+	}
+	return ep.host
+}
+
+// Parse and normalize host pattern
+func parseHostPattern(pattern string) (*hostTable, error) {
+	pattern = strings.TrimSuffix(strings.ToLower(pattern), ".")
+	if pattern == "" {
+		return nil, fmt.Errorf("host pattern cannot be empty")
+	}
+
+	table := &hostTable{
+		pattern: pattern,
+		labels:  strings.Split(pattern, "."),
+	}
+	for i, label := range table.labels {
+		if label == "" {
+			return nil, fmt.Errorf("empty label in host pattern %q", pattern)
+		}
+		if !strings.HasPrefix(label, "{") {
+			if strings.ContainsAny(label, "{}:/") {
+				return nil, fmt.Errorf("invalid label %q in host pattern %q", label, pattern)
+			}
+			continue
+		}
+
+		// Parameter label
+		name := strings.TrimPrefix(label, "{")
+		if !strings.HasSuffix(name, "}") {
+			return nil, fmt.Errorf("unterminated parameter in host pattern %q", pattern)
+		}
+		name = strings.TrimSuffix(name, "}")
+		if name == "" {
+			return nil, fmt.Errorf("parameter name cannot be empty")
+		}
+		for j := 0; j < len(name); j++ {
+			if !isValidParamChar(name[j]) {
+				return nil, fmt.Errorf("invalid character '%c' in parameter name", name[j])
+			}
+		}
+		for _, key := range table.keys {
+			if key == name {
+				return nil, fmt.Errorf("duplicate parameter %q in host pattern %q", name, pattern)
+			}
+		}
+		table.labels[i] = ""
+		table.keys = append(table.keys, name)
+	}
+
+	return table, nil
+}
+
+// Number of static labels
+func (table *hostTable) staticLabels() int {
+	return len(table.labels) - len(table.keys)
+}
+
+// match reports whether host matches the pattern and returns the parameter values
+func (table *hostTable) match(host string) ([]string, bool) {
+	var values []string
+	for i, label := range table.labels {
+		end := strings.IndexByte(host, '.')
+		if i == len(table.labels)-1 {
+			end = len(host)
+		} else if end == -1 {
+			return nil, false
+		}
+
+		value := host[:end]
+		if label == "" {
+			if value == "" || strings.IndexByte(value, '.') >= 0 {
+				return nil, false
+			}
+			if values == nil {
+				values = make([]string, 0, len(table.keys))
+			}
+			values = append(values, value)
+		} else if value != label {
+			return nil, false
+		}
+
+		if end < len(host) {
+			host = host[end+1:]
+		} else {
+			host = ""
+		}
+	}
+	return values, true
+}
+
+// hostTable returns the table for the host pattern, creating it when needed
+func (data *trieData) hostTable(pattern string) *hostTable {
+	for _, table := range data.hosts {
+		if table.pattern == pattern {
+			return table
+		}
+	}
+
+	// Already validated by Mux.Host
+	table, _ := parseHostPattern(pattern)
+	table.data = newTrieData()
+	data.hosts = append(data.hosts, table)
+	data.indexHosts()
+	return table
+}
+
+// Index host tables for matching
+func (data *trieData) indexHosts() {
+	data.hostExact = make(map[string]*hostTable)
+	data.hostWild = data.hostWild[:0:0]
+	for _, table := range data.hosts {
+		if len(table.keys) == 0 {
+			data.hostExact[table.pattern] = table
+		} else {
+			data.hostWild = append(data.hostWild, table)
+		}
+	}
+
+	// More static labels first, then registration order
+	sort.SliceStable(data.hostWild, func(i, j int) bool {
+		return data.hostWild[i].staticLabels() > data.hostWild[j].staticLabels()
+	})
+}
+
+// matchHost returns the table matching the request host and the values of
+// its parameters, or nil when no host pattern matches
+func (data *trieData) matchHost(host string) (*hostTable, []string) {
+	host = normalizeHost(host)
+	if table, ok := data.hostExact[host]; ok {
+		return table, nil
+	}
+	for _, table := range data.hostWild {
+		if values, ok := table.match(host); ok {
+			return table, values
+		}
+	}
+	return nil, nil
+}
+
+// Strip port and trailing dot, and lower case host
+func normalizeHost(host string) string {
+	if i := strings.LastIndexByte(host, ':'); i > strings.LastIndexByte(host, ']') {
+		host = host[:i]
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// withHostParams adds the parameters of the matched host to ctx
+func (m *Mux) withHostParams(ctx *Context, keys, values []string) *Context {
+	if len(values) == 0 {
+		return ctx
+	}
+	if ctx == nil {
+		ctx = m.contextPool.Get().(*Context)
+	}
+	for i, key := range keys {
+		ctx.PutParam(key, values[i])
+	}
+	return ctx
+}
@@ -0,0 +1,240 @@
+package bon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test routing by host
+func TestMuxHost(t *testing.T) {
+	r := NewRouter()
+
+	r.Get("/", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("default"))
+	})
+	r.Get("/only-default", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("only-default"))
+	})
+
+	api := r.Host("api.example.com")
+	api.Get("/", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("api"))
+	})
+	api.Get("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("api user " + URLParam(req, "id")))
+	})
+
+	tenant := r.Host("{tenant}.example.com")
+	tenant.Get("/", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("tenant " + URLParam(req, "tenant")))
+	})
+	tenant.Get("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(URLParam(req, "tenant") + " user " + URLParam(req, "id")))
+	})
+
+	region := r.Host("{tenant}.{region}.example.com")
+	region.Get("/", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(URLParam(req, "tenant") + "@" + URLParam(req, "region")))
+	})
+
+	tests := []struct {
+		host     string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		// Exact host beats the wildcard host
+		{"api.example.com", "/", http.StatusOK, "api"},
+		{"API.Example.com:8080", "/users/1", http.StatusOK, "api user 1"},
+		{"acme.example.com", "/", http.StatusOK, "tenant acme"},
+		{"acme.example.com.", "/users/2", http.StatusOK, "acme user 2"},
+		{"acme.eu.example.com", "/", http.StatusOK, "acme@eu"},
+		// Matched hosts only use their own routes
+		{"acme.example.com", "/only-default", http.StatusNotFound, "404 page not found\n"},
+		// Unmatched hosts fall back to the default routes
+		{"example.com", "/", http.StatusOK, "default"},
+		{"other.org", "/only-default", http.StatusOK, "only-default"},
+		{"a.b.c.example.com", "/", http.StatusOK, "default"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		req.Host = tt.host
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s%s: expected status %d, got %d", tt.host, tt.path, tt.wantCode, w.Code)
+		}
+		if w.Body.String() != tt.wantBody {
+			t.Errorf("%s%s: expected body %q, got %q", tt.host, tt.path, tt.wantBody, w.Body.String())
+		}
+	}
+}
+
+// Test host routes with groups, middleware and method handling
+func TestMuxHostGroup(t *testing.T) {
+	r := NewRouter()
+	r.Use(WriteMiddleware("G"))
+
+	admin := r.Host("admin.example.com")
+	admin.Use(WriteMiddleware("A"))
+	users := admin.Group("/users", WriteMiddleware("U"))
+	users.Get("/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(URLParam(req, "id")))
+	})
+	admin.Route().Post("/login", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("login"))
+	})
+
+	tests := []struct {
+		method    string
+		path      string
+		wantCode  int
+		wantBody  string
+		wantAllow string
+	}{
+		{"GET", "/users/7", http.StatusOK, "GAU7", ""},
+		{"POST", "/login", http.StatusOK, "Glogin", ""},
+		// Global middleware writes first, so the status is 200
+		{"GET", "/login", http.StatusOK, "GMethod Not Allowed\n", "OPTIONS, POST"},
+		{"HEAD", "/users/7", http.StatusOK, "", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Host = "admin.example.com"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.wantCode, w.Code)
+		}
+		if w.Body.String() != tt.wantBody {
+			t.Errorf("%s %s: expected body %q, got %q", tt.method, tt.path, tt.wantBody, w.Body.String())
+		}
+		if got := w.Header().Get("Allow"); got != tt.wantAllow {
+			t.Errorf("%s %s: expected Allow %q, got %q", tt.method, tt.path, tt.wantAllow, got)
+		}
+	}
+
+	// The same routes are not registered for other hosts
+	req := httptest.NewRequest("GET", "/users/7", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if got := w.Body.String(); got != "G404 page not found\n" {
+		t.Errorf("Expected not found for default host, got %q", got)
+	}
+
+	var hosts []string
+	for route := range r.Routes() {
+		hosts = append(hosts, route.Host)
+	}
+	if len(hosts) != 2 || hosts[0] != "admin.example.com" || hosts[1] != "admin.example.com" {
+		t.Errorf("Expected host routes in Routes(), got %v", hosts)
+	}
+}
+
+// Test registering host routes after requests were served
+func TestMuxHostCopyOnWrite(t *testing.T) {
+	r := NewRouter()
+	api := r.Host("api.example.com")
+	api.Get("/a", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("a"))
+	})
+
+	before := r.doubleArray.load()
+	api.Get("/b", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("b"))
+	})
+
+	// Published snapshots are not modified
+	if table, _ := before.matchHost("api.example.com"); table == nil || len(table.data.endpoints) != 1 {
+		t.Fatal("Expected previous snapshot to keep one host route")
+	}
+
+	for _, path := range []string{"/a", "/b"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = "api.example.com"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: expected status 200, got %d", path, w.Code)
+		}
+	}
+}
+
+// Test removing and updating the routes of a host
+func TestMuxHostRemove(t *testing.T) {
+	get := func(r *Mux, host, path string) string {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = host
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	r := NewRouter()
+	r.Get("/x", orderHandler("main"))
+	api := r.Host("API.example.com")
+	api.Get("/x", orderHandler("api"))
+	api.Group("/v1").Get("/y", orderHandler("v1"))
+
+	if r.Remove("GET", "/y") || r.Remove("GET", "/v1/y") {
+		t.Error("Expected Mux.Remove to leave host routes")
+	}
+	if !api.Group("/v1").Remove("GET", "/y") {
+		t.Error("Expected GET api.example.com/v1/y to be removed")
+	}
+	if r.Host("api.example.com").Remove("GET", "/v1/y") {
+		t.Error("Expected second removal to report false")
+	}
+	if got := get(r, "api.example.com", "/v1/y"); got != "404 page not found\n" {
+		t.Errorf("Expected removed host route, got %q", got)
+	}
+
+	err := r.Update(func(tx *Tx) error {
+		if !tx.Host("api.example.com").Remove("GET", "/x") {
+			t.Error("Expected GET api.example.com/x to be removed")
+		}
+		tx.Host("{tenant}.example.com").Get("/x", orderHandler("tenant"))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for host, want := range map[string]string{
+		"api.example.com":  "tenant",
+		"acme.example.com": "tenant",
+		"api.test":         "main",
+	} {
+		if got := get(r, host, "/x"); got != want {
+			t.Errorf("%s: expected %q, got %q", host, want, got)
+		}
+	}
+}
+
+// Test invalid host patterns
+func TestMuxHostInvalidPatterns(t *testing.T) {
+	patterns := []string{
+		"",
+		"example..com",
+		"{}.example.com",
+		"{tenant.example.com",
+		"x{tenant}.example.com",
+		"{a}.{a}.example.com",
+		"example.com:8080",
+	}
+
+	for _, pattern := range patterns {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for host pattern %q", pattern)
+				}
+			}()
+			NewRouter().Host(pattern)
+		}()
+	}
+}
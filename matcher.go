@@ -0,0 +1,240 @@
+package bon
+
+import (
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// requestMatcher restricts a route to requests beyond method and path
+type requestMatcher struct {
+	key   string // Identity of the matcher within the route key
+	match func(r *http.Request) bool
+}
+
+// Sequence distinguishing the predicates of MatchFunc
+var matchFuncSeq atomic.Uint64
+
+// MatchHeader restricts the route to requests with a header key whose value
+// is value.
+//
+//	r.With(bon.MatchHeader("Accept", "application/vnd.api.v2+json")).Get("/users", listUsersV2)
+//	r.Get("/users", listUsers)
+//
+// Routes sharing a method and pattern are tried from the one with the most
+// matchers to the one with the fewest, so the route without matchers is the
+// fallback. When no route of a pattern matches the request, the path is
+// treated as having no route for the method, which affects 404 and 405
+// responses alike.
+func MatchHeader(key, value string) RouteOption {
+	if key == "" {
+		panic("bon: header name cannot be empty")
+	}
+	key = http.CanonicalHeaderKey(key)
+	return withMatcher(requestMatcher{
+		key: "header " + key + "=" + value,
+		match: func(r *http.Request) bool {
+			for _, v := range r.Header.Values(key) {
+				if v == value {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+// MatchHeaderRegexp restricts the route to requests with a header key whose
+// value matches the regular expression expr. See MatchHeader.
+func MatchHeaderRegexp(key, expr string) RouteOption {
+	if key == "" {
+		panic("bon: header name cannot be empty")
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		panic("bon: invalid header pattern " + strconv.Quote(expr) + ": " + err.Error())
+	}
+	key = http.CanonicalHeaderKey(key)
+	return withMatcher(requestMatcher{
+		key: "header " + key + "~" + expr,
+		match: func(r *http.Request) bool {
+			for _, v := range r.Header.Values(key) {
+				if re.MatchString(v) {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+// MatchQuery restricts the route to requests whose query string has the
+// parameter key, with any value. See MatchHeader.
+func MatchQuery(key string) RouteOption {
+	if key == "" {
+		panic("bon: query parameter name cannot be empty")
+	}
+	return withMatcher(requestMatcher{
+		key: "query " + key,
+		match: func(r *http.Request) bool {
+			return r.URL.Query().Has(key)
+		},
+	})
+}
+
+// MatchContentType restricts the route to requests whose Content-Type media
+// type is one of types, ignoring case and parameters such as charset. See
+// MatchHeader.
+func MatchContentType(types ...string) RouteOption {
+	if len(types) == 0 {
+		panic("bon: content types cannot be empty")
+	}
+	types = normalizeValues(types)
+	return withMatcher(requestMatcher{
+		key: "content-type " + strings.Join(types, ","),
+		match: func(r *http.Request) bool {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			return err == nil && slices.Contains(types, mediaType)
+		},
+	})
+}
+
+// MatchScheme restricts the route to requests made with one of schemes
+// ("http" or "https"). The scheme is taken from the request URL when it is
+// absolute, and is otherwise "https" for TLS connections and "http" for the
+// others. See MatchHeader.
+func MatchScheme(schemes ...string) RouteOption {
+	if len(schemes) == 0 {
+		panic("bon: schemes cannot be empty")
+	}
+	schemes = normalizeValues(schemes)
+	return withMatcher(requestMatcher{
+		key: "scheme " + strings.Join(schemes, ","),
+		match: func(r *http.Request) bool {
+			return slices.Contains(schemes, requestScheme(r))
+		},
+	})
+}
+
+// MatchFunc restricts the route to requests for which fn returns true. Each
+// call of MatchFunc creates a distinct matcher, so routes registered with
+// different calls never replace each other. See MatchHeader.
+func MatchFunc(fn func(r *http.Request) bool) RouteOption {
+	if fn == nil {
+		panic("bon: match function cannot be nil")
+	}
+	return withMatcher(requestMatcher{
+		key:   "func " + strconv.FormatUint(matchFuncSeq.Add(1), 10),
+		match: fn,
+	})
+}
+
+// Route option adding a request matcher
+func withMatcher(matcher requestMatcher) RouteOption {
+	return func(ep *endpoint) {
+		ep.matchers = append(ep.matchers, matcher)
+	}
+}
+
+// matcherKey identifies the matchers of ep independently of their order
+func (ep *endpoint) matcherKey() string {
+	if len(ep.matchers) == 0 {
+		return ""
+	}
+	keys := make([]string, len(ep.matchers))
+	for i, matcher := range ep.matchers {
+		keys[i] = matcher.key
+	}
+	sort.Strings(keys)
+	return " [" + strings.Join(keys, "; ") + "]"
+}
+
+// matchRequest reports whether r satisfies all matchers of ep
+func (ep *endpoint) matchRequest(r *http.Request) bool {
+	for _, matcher := range ep.matchers {
+		if !matcher.match(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// addVariant groups the route at idx with the routes sharing its method and
+// pattern once one of them has request matchers. It reports whether the route
+// joined the lookup entry of an earlier route instead of getting its own.
+func (data *trieData) addVariant(ep *endpoint, idx int) bool {
+	key := ep.method + ep.pattern
+	primary, grouped := data.primary[key]
+	if !grouped {
+		if len(ep.matchers) == 0 {
+			return false
+		}
+		if data.primary == nil {
+			data.primary = make(map[string]int)
+			data.variants = make(map[int][]int)
+		}
+		plain, exists := data.routes[key]
+		if !exists {
+			// First route of the pattern keeps its own lookup entry
+			data.primary[key] = idx
+			data.variants[idx] = []int{idx}
+			return false
+		}
+		primary = plain
+		data.primary[key] = plain
+		data.variants[plain] = []int{plain}
+	}
+
+	// Most matchers first, then registration order
+	variants := data.variants[primary]
+	pos := len(variants)
+	for i, v := range variants {
+		if len(data.endpoints[v].matchers) < len(ep.matchers) {
+			pos = i
+			break
+		}
+	}
+	data.variants[primary] = slices.Insert(variants, pos, idx)
+	return true
+}
+
+// choose returns the endpoint at idx or, when routes with request matchers
+// share its method and pattern, the first of them matching r. It returns nil
+// when none matches.
+func (data *trieData) choose(idx int, r *http.Request) *endpoint {
+	variants, ok := data.variants[idx]
+	if !ok {
+		return data.endpoints[idx]
+	}
+	for _, v := range variants {
+		if ep := data.endpoints[v]; ep.matchRequest(r) {
+			return ep
+		}
+	}
+	return nil
+}
+
+// Scheme of the request
+func requestScheme(r *http.Request) string {
+	if r.URL.Scheme != "" {
+		return strings.ToLower(r.URL.Scheme)
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// Lower case values
+func normalizeValues(values []string) []string {
+	normalized := make([]string, len(values))
+	for i, v := range values {
+		normalized[i] = strings.ToLower(v)
+	}
+	return normalized
+}
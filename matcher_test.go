@@ -0,0 +1,137 @@
+package bon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test routes sharing a pattern selected by request matchers
+func TestMuxRequestMatchers(t *testing.T) {
+	r := NewRouter()
+
+	r.Get("/users", orderHandler("v1"))
+	r.With(MatchHeader("Accept", "application/vnd.api.v2+json")).Get("/users", orderHandler("v2"))
+	r.With(MatchHeaderRegexp("accept", `^application/vnd\.api\.v3`)).Get("/users", orderHandler("v3"))
+	// More matchers win over fewer
+	r.With(MatchHeader("Accept", "application/vnd.api.v2+json"), MatchQuery("debug")).Get("/users", orderHandler("v2 debug"))
+
+	r.With(MatchContentType("application/json")).Post("/hooks/:id", orderHandler("json"))
+	r.With(MatchContentType("application/x-www-form-urlencoded", "multipart/form-data")).Post("/hooks/:id", orderHandler("form"))
+	r.Post("/hooks/github", orderHandler("github"))
+	r.Get("/hooks/:id", orderHandler("get"))
+
+	r.With(MatchScheme("https")).Get("/secure", orderHandler("secure"))
+	r.With(MatchFunc(func(req *http.Request) bool {
+		return req.Header.Get("X-Beta") == "1"
+	})).Get("/items/:id", orderHandler("beta"))
+	r.Get("/items/*", orderHandler("wildcard"))
+
+	tests := []struct {
+		method    string
+		path      string
+		header    http.Header
+		wantCode  int
+		wantBody  string
+		wantAllow string
+	}{
+		{"GET", "/users", nil, http.StatusOK, "v1", ""},
+		{"GET", "/users", http.Header{"Accept": {"application/vnd.api.v2+json"}}, http.StatusOK, "v2", ""},
+		{"GET", "/users?debug", http.Header{"Accept": {"application/vnd.api.v2+json"}}, http.StatusOK, "v2 debug", ""},
+		{"GET", "/users?debug", nil, http.StatusOK, "v1", ""},
+		{"GET", "/users", http.Header{"Accept": {"application/vnd.api.v3+json"}}, http.StatusOK, "v3", ""},
+		{"HEAD", "/users", http.Header{"Accept": {"application/vnd.api.v2+json"}}, http.StatusOK, "", ""},
+		{"POST", "/hooks/stripe", http.Header{"Content-Type": {"application/json; charset=utf-8"}}, http.StatusOK, "json", ""},
+		{"POST", "/hooks/stripe", http.Header{"Content-Type": {"Multipart/Form-Data; boundary=x"}}, http.StatusOK, "form", ""},
+		// Static route without matchers beats parameter routes with matchers
+		{"POST", "/hooks/github", http.Header{"Content-Type": {"application/json"}}, http.StatusOK, "github", ""},
+		// No route of the pattern matches the request
+		{"POST", "/hooks/stripe", http.Header{"Content-Type": {"text/xml"}}, http.StatusMethodNotAllowed, "Method Not Allowed\n", "GET, HEAD, OPTIONS"},
+		{"GET", "/secure", nil, http.StatusNotFound, "404 page not found\n", ""},
+		{"GET", "https://example.com/secure", nil, http.StatusOK, "secure", ""},
+		{"GET", "/items/1", http.Header{"X-Beta": {"1"}}, http.StatusOK, "beta", ""},
+		// Failed matchers fall through to the next candidate route
+		{"GET", "/items/1", nil, http.StatusOK, "wildcard", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		for key, values := range tt.header {
+			req.Header[key] = values
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s %s %v: expected status %d, got %d", tt.method, tt.path, tt.header, tt.wantCode, w.Code)
+		}
+		if w.Body.String() != tt.wantBody {
+			t.Errorf("%s %s %v: expected body %q, got %q", tt.method, tt.path, tt.header, tt.wantBody, w.Body.String())
+		}
+		if got := w.Header().Get("Allow"); got != tt.wantAllow {
+			t.Errorf("%s %s %v: expected Allow %q, got %q", tt.method, tt.path, tt.header, tt.wantAllow, got)
+		}
+	}
+}
+
+// Test replacing and removing routes with request matchers
+func TestMuxRequestMatchersReplaceRemove(t *testing.T) {
+	r := NewRouter()
+	json := MatchContentType("application/json")
+
+	// Routes with matchers registered before the route without
+	r.With(json).Post("/hooks", orderHandler("old json"))
+	r.Post("/hooks", orderHandler("plain"))
+	r.With(MatchContentType("APPLICATION/JSON")).Post("/hooks", orderHandler("json"))
+
+	serve := func(contentType string) string {
+		req := httptest.NewRequest("POST", "/hooks", nil)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+	if got := serve("application/json"); got != "json" {
+		t.Errorf("Expected replaced json route, got %q", got)
+	}
+	if got := serve("text/plain"); got != "plain" {
+		t.Errorf("Expected route without matchers, got %q", got)
+	}
+	if n := len(r.doubleArray.load().endpoints); n != 2 {
+		t.Errorf("Expected 2 routes, got %d", n)
+	}
+
+	// Remove drops the routes with matchers as well
+	if !r.Remove("POST", "/hooks") {
+		t.Fatal("Expected routes to be removed")
+	}
+	if got := serve("application/json"); got != "404 page not found\n" {
+		t.Errorf("Expected not found after remove, got %q", got)
+	}
+}
+
+// Test invalid request matchers
+func TestMuxRequestMatchersInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		option func() RouteOption
+	}{
+		{"empty header", func() RouteOption { return MatchHeader("", "x") }},
+		{"invalid regexp", func() RouteOption { return MatchHeaderRegexp("Accept", "(") }},
+		{"empty query", func() RouteOption { return MatchQuery("") }},
+		{"no content types", func() RouteOption { return MatchContentType() }},
+		{"no schemes", func() RouteOption { return MatchScheme() }},
+		{"nil func", func() RouteOption { return MatchFunc(nil) }},
+	}
+
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", tt.name)
+				}
+			}()
+			tt.option()
+		}()
+	}
+}
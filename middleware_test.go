@@ -359,6 +359,63 @@ func TestMiddlewarePerformanceImpact(t *testing.T) {
 	}
 }
 
+// Pre-routing middleware test
+func TestMuxPre(t *testing.T) {
+	r := NewRouter()
+	var preRoute bool
+	// Strip the locale prefix and the www. host prefix before routing
+	r.Pre(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, preRoute = RouteInfo(req)
+			if rest, ok := strings.CutPrefix(req.URL.Path, "/en/"); ok {
+				req.URL.Path = "/" + rest
+				req.Header.Set("Locale", "en")
+			}
+			req.Host = strings.TrimPrefix(req.Host, "www.")
+			next.ServeHTTP(w, req)
+		})
+	}, orderMiddleware("P1"))
+	r.Pre(orderMiddleware("P2"))
+	r.Use(orderMiddleware("G"))
+
+	r.Get("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("user " + URLParam(req, "id") + " " + req.Header.Get("Locale") + " " + RoutePattern(req)))
+	})
+	r.Host("api.test").Get("/", orderHandler("host"))
+	r.SetNotFound(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("not found " + req.URL.Path))
+	})
+
+	sub := NewRouter()
+	sub.Pre(orderMiddleware("S"))
+	sub.Get("/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("item " + URLParam(req, "id")))
+	})
+	r.Mount("/items", sub)
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"/users/1", "P1→P2→G→user 1  /users/:id"},
+		{"/en/users/1", "P1→P2→G→user 1 en /users/:id"},
+		{"http://www.api.test/", "P1→P2→G→host"},
+		{"/en/missing", "P1→P2→G→not found /missing"},
+		{"/en/items/7", "P1→P2→G→S→item 7"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+		if got := w.Body.String(); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.target, tt.want, got)
+		}
+	}
+	if preRoute {
+		t.Error("Expected no route before routing")
+	}
+}
+
 // Helper function: Individual request verification
 func VerifyRequest(handler http.Handler, req *http.Request, expectedStatus int, expectedBody string) error {
 	rec := httptest.NewRecorder()
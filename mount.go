@@ -0,0 +1,140 @@
+package bon
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// methodAny is the method of mounted handler routes, which match any method
+const methodAny = "*"
+
+// mount strips the mount prefix from the request path before dispatch
+type mount struct {
+	mux     *Mux // Mux the handler is mounted on
+	handler http.Handler
+	depth   int // Number of path segments in the mount prefix
+}
+
+// Mount dispatches requests for any method whose path is prefix or starts
+// with prefix + "/" to handler, with prefix stripped from URL.Path and
+// URL.RawPath (from the escaped path with UseRawPath). The mount routes take
+// priority over other routes by their pattern like any route, and a route
+// registered for the request method wins when both are as specific (see
+// calculateScore).
+//
+// When handler is a *Mux, its URL parameters are merged with those of the
+// prefix, and its 404 handler falls back to the 404 handler of this Mux
+// unless one is set on it with SetNotFound.
+func (m *Mux) Mount(prefix string, handler http.Handler, middlewares ...Middleware) {
+	mountHandle(m, prefix, m.newMount(prefix, handler), middlewares...)
+}
+
+// Register mount routes for prefix and the paths below it
+func mountHandle(r Router, prefix string, handler http.Handler, middlewares ...Middleware) {
+	p := strings.TrimSuffix(resolvePatternPrefix(prefix), "/")
+	if p == "" {
+		r.Handle(methodAny, "/", handler, middlewares...)
+	} else {
+		r.Handle(methodAny, p, handler, middlewares...)
+	}
+	r.Handle(methodAny, p+"/*", handler, middlewares...)
+}
+
+// newMount creates mount handler for the full prefix
+func (m *Mux) newMount(prefix string, handler http.Handler) *mount {
+	p := strings.TrimSuffix(resolvePatternPrefix(prefix), "/")
+	if strings.HasSuffix(p, "*") {
+		panic("bon: mount prefix cannot end with a wildcard")
+	}
+	if sub, ok := handler.(*Mux); ok {
+		if sub == m {
+			panic("bon: cannot mount a Mux on itself")
+		}
+		sub.parent = m
+		sub.notFoundChain = sub.buildNotFoundChain()
+	}
+
+	// Constraints may contain slashes
+	if plain, _, err := compileConstraints(p); err == nil {
+		p = plain
+	}
+	return &mount{
+		mux:     m,
+		handler: handler,
+		depth:   strings.Count(p, "/"),
+	}
+}
+
+func (mt *mount) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	if mt.mux.UseRawPath {
+		// The prefix was matched against the escaped path, where an encoded
+		// slash does not separate segments
+		raw := stripSegments(r.URL.EscapedPath(), mt.depth)
+		if p, err := url.PathUnescape(raw); err == nil {
+			r2.URL.Path = p
+			r2.URL.RawPath = raw
+			if (&url.URL{Path: p}).EscapedPath() == raw {
+				r2.URL.RawPath = ""
+			}
+			mt.handler.ServeHTTP(w, r2)
+			return
+		}
+	}
+	r2.URL.Path = stripSegments(r.URL.Path, mt.depth)
+	if r.URL.RawPath != "" {
+		r2.URL.RawPath = stripSegments(r.URL.RawPath, mt.depth)
+		// Drop the raw path when it no longer encodes the path
+		if p, err := url.PathUnescape(r2.URL.RawPath); err != nil || p != r2.URL.Path {
+			r2.URL.RawPath = ""
+		}
+	}
+	mt.handler.ServeHTTP(w, r2)
+}
+
+// Remove the first n segments from path
+func stripSegments(path string, n int) string {
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' {
+			if n == 0 {
+				return path[i:]
+			}
+			n--
+		}
+	}
+	return "/"
+}
+
+// Build 404 handler chain, falling back to the parent's 404 handler when
+// mounted without its own. The parent's middlewares already ran for the mount.
+func (m *Mux) buildNotFoundChain() http.Handler {
+	if m.parent != nil && !m.notFoundSet {
+		return buildMiddlewareChain(http.HandlerFunc(m.parent.notFound), m.middlewares)
+	}
+	return buildMiddlewareChain(m.NotFound, m.middlewares)
+}
+
+// notFound runs the effective 404 handler without middlewares
+func (m *Mux) notFound(w http.ResponseWriter, r *http.Request) {
+	if m.parent != nil && !m.notFoundSet {
+		m.parent.notFound(w, r)
+		return
+	}
+	m.NotFound(w, r)
+}
+
+// inherit adds the parameters of the Mux the request was mounted from,
+// except the path captured by the wildcard of the mount route
+func (ctx *Context) inherit(r *http.Request) {
+	if parent, ok := r.Context().Value(contextKey).(*Context); ok && parent != ctx {
+		for i, key := range parent.params.keys {
+			if key != WildcardKey {
+				ctx.PutParam(key, parent.params.values[i])
+			}
+		}
+	}
+}
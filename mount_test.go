@@ -0,0 +1,212 @@
+package bon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test mounting a handler with path stripping
+func TestMuxMount(t *testing.T) {
+	r := NewRouter()
+
+	echo := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(req.Method + " " + req.URL.Path + " " + req.URL.RawPath))
+	})
+	r.Mount("/svc", echo)
+	r.Get("/svc/health", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("health"))
+	})
+
+	tests := []struct {
+		method   string
+		path     string
+		wantBody string
+	}{
+		{"GET", "/svc", "GET / "},
+		{"GET", "/svc/", "GET / "},
+		{"POST", "/svc/users/1", "POST /users/1 "},
+		{"PROPFIND", "/svc/dav", "PROPFIND /dav "},
+		{"GET", "/svc/a%2Fb", "GET /a/b /a%2Fb"},
+		// Routes for a specific method take priority
+		{"GET", "/svc/health", "health"},
+		{"DELETE", "/svc/health", "DELETE /health "},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("%s %s: expected status 200, got %d", tt.method, tt.path, w.Code)
+		}
+		if w.Body.String() != tt.wantBody {
+			t.Errorf("%s %s: expected body %q, got %q", tt.method, tt.path, tt.wantBody, w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/svcx", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET /svcx: expected status 404, got %d", w.Code)
+	}
+}
+
+// Test mounting a sub-Mux under a parameterized prefix
+func TestMuxMountSubMux(t *testing.T) {
+	sub := NewRouter()
+	sub.Get("/", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("index " + URLParam(req, "tenant")))
+	})
+	sub.Get("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(URLParam(req, "tenant") + " user " + URLParam(req, "id")))
+	})
+	sub.Post("/users", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("create"))
+	})
+	// The path captured by the mount route is not a parameter of the sub-Mux
+	sub.Get("/about", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("about " + URLParam(req, WildcardKey)))
+	})
+	sub.Get("/files/*", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("file " + URLParam(req, WildcardKey)))
+	})
+
+	r := NewRouter()
+	r.SetNotFound(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("parent 404 " + req.URL.Path))
+	})
+	api := r.Group("/api")
+	api.Mount("/tenants/:tenant", sub)
+
+	tests := []struct {
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"GET", "/api/tenants/acme", http.StatusOK, "index acme"},
+		{"GET", "/api/tenants/acme/users/7", http.StatusOK, "acme user 7"},
+		{"POST", "/api/tenants/acme/users", http.StatusOK, "create"},
+		{"GET", "/api/tenants/acme/about", http.StatusOK, "about "},
+		{"GET", "/api/tenants/acme/files/a/b", http.StatusOK, "file a/b"},
+		{"DELETE", "/api/tenants/acme/users", http.StatusMethodNotAllowed, "Method Not Allowed\n"},
+		// Sub-Mux 404 falls back to the parent's handler
+		{"GET", "/api/tenants/acme/missing", http.StatusNotFound, "parent 404 /missing"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.wantCode, w.Code)
+		}
+		if w.Body.String() != tt.wantBody {
+			t.Errorf("%s %s: expected body %q, got %q", tt.method, tt.path, tt.wantBody, w.Body.String())
+		}
+	}
+
+	// A 404 handler set on the sub-Mux takes priority
+	sub.SetNotFound(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("sub 404"))
+	})
+	req := httptest.NewRequest("GET", "/api/tenants/acme/missing", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Body.String() != "sub 404" {
+		t.Errorf("Expected sub-Mux 404 handler, got %q", w.Body.String())
+	}
+}
+
+// Test middleware order for mounted sub-Mux
+func TestMuxMountMiddleware(t *testing.T) {
+	sub := NewRouter()
+	sub.Use(WriteMiddleware("S"))
+	sub.Get("/x", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("x"))
+	})
+
+	r := NewRouter()
+	r.Use(WriteMiddleware("P"))
+	r.Mount("/sub", sub, WriteMiddleware("M"))
+
+	if err := VerifyExtended(r, []*Want{
+		{"GET:/sub/x", 200, "PMSx"},
+		// Parent middlewares run once for the fallback 404
+		{"GET:/sub/y", 200, "PMS404 page not found\n"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test that mounts compete with the routes of the method on priority
+func TestMuxMountPriority(t *testing.T) {
+	api := NewRouter()
+	api.Get("/users", orderHandler("api users"))
+
+	r := NewRouter()
+	r.Get("/*", orderHandler("spa"))
+	r.Mount("/api", api)
+	r.Get("/files/:name", orderHandler("get file"))
+	r.Mount("/files", http.HandlerFunc(orderHandler("files mount")))
+	r.Get("/files/*", orderHandler("get files"))
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{"GET", "/api/users", "api users"},
+		{"HEAD", "/api/users", ""},
+		{"POST", "/api/users", "Method Not Allowed\n"},
+		{"GET", "/about", "spa"},
+		{"GET", "/files/a.txt", "get file"},
+		{"DELETE", "/files/a.txt", "files mount"},
+		// The route of the method wins over an equally specific mount
+		{"GET", "/files/a/b.txt", "get files"},
+		{"PUT", "/files/a/b.txt", "files mount"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Body.String() != tt.want {
+			t.Errorf("%s %s: expected %q, got %q", tt.method, tt.path, tt.want, w.Body.String())
+		}
+	}
+
+	// HEAD is answered by the sub-Mux GET route, not the SPA
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("HEAD", "/api/users", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Allow") != "" {
+		t.Errorf("HEAD /api/users: unexpected response %d %v", w.Code, w.Header())
+	}
+}
+
+// Test invalid mount prefixes
+func TestMuxMountInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		mount func(r *Mux)
+	}{
+		{"wildcard prefix", func(r *Mux) { r.Mount("/files/*", http.NotFoundHandler()) }},
+		{"self", func(r *Mux) { r.Mount("/self", r) }},
+	}
+
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", tt.name)
+				}
+			}()
+			tt.mount(NewRouter())
+		}()
+	}
+}
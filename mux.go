@@ -3,6 +3,8 @@ package bon
 import (
 	"fmt"
 	"net/http"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -29,13 +31,40 @@ type (
 	// Mux is the main HTTP router structure
 	Mux struct {
 		doubleArray     *doubleArrayTrie // Double array trie for routing
-		endpoints       []*endpoint      // Slice of registered endpoints
 		middlewares     []Middleware     // Global middlewares
+		preMiddlewares  []Middleware     // Middlewares run before routing (see Pre)
+		preChain        http.Handler     // Pre-built chain of preMiddlewares ending in route (nil when empty)
 		contextPool     sync.Pool        // Pool for Context reuse
 		paramBufferPool sync.Pool        // Pool for parameter buffers
 		maxParam        int              // Maximum parameter count (dynamically updated)
 		NotFound        http.HandlerFunc // 404 handler
 		notFoundChain   http.Handler     // Pre-built 404 handler chain
+		// 405 handler, used when the path matches under other methods only (see SetMethodNotAllowed)
+		methodNotAllowedHandler http.HandlerFunc
+		methodNotAllowedChain   http.Handler // Pre-built 405 handler chain
+		// Automatic OPTIONS handler, used when no Options route is registered
+		// (nil disables, see SetAutomaticOptions)
+		automaticOptionsHandler http.HandlerFunc
+		optionsChain            http.Handler // Pre-built automatic OPTIONS handler chain
+		// Answer HEAD requests with the GET route when no Head route is registered
+		ImplicitHead bool
+		// Redirect to the path with the trailing slash added or removed on a miss
+		RedirectTrailingSlash bool
+		// Redirect to the cleaned path (no . and .. elements or repeated slashes) on a miss
+		RedirectFixedPath bool
+		// Redirect to the path of a route matching case-insensitively on a miss
+		RedirectCaseInsensitive bool
+		// Panic for duplicate and shadowed routes instead of replacing or adding them
+		StrictRoutes bool
+		// Also set the route parameters as request path values (see http.Request.PathValue)
+		PathValues bool
+		// Match routes against the escaped path, so that an encoded slash (%2F)
+		// does not separate segments, and unescape the parameters
+		UseRawPath bool
+		// Handling of encoded slashes when UseRawPath is set
+		EncodedSlashes EncodedSlashPolicy
+		parent         *Mux // Mux this Mux is mounted on (nil when not mounted)
+		notFoundSet    bool // 404 handler set with SetNotFound
 	}
 
 	nodeKind uint8
@@ -45,18 +74,32 @@ type (
 		// Atomic pointer to the current trie data for lock-free reads
 		data atomic.Pointer[trieData]
 		mu   sync.Mutex // Mutex for write operations only
+		// Route changes not yet built into data (nil when none, see Mux.Compile)
+		pending *txState
+		staged  atomic.Bool // Whether pending is set (read without the lock)
 	}
 
 	// trieData holds the actual trie arrays and maps
 	trieData struct {
-		base      []int32          // Base array for trie
-		check     []int32          // Check array for state verification
-		routes    map[string]int   // "METHOD/path" -> endpoint index mapping
-		staticMap map[string]int   // Fast lookup map for static routes
-		prefixMap map[string][]int // Prefix map for dynamic routes
+		base      []int32        // Base array for trie
+		check     []int32        // Check array for state verification
+		routes    map[string]int // "METHOD/path" -> endpoint index mapping
+		staticMap map[string]int // Fast lookup map for static routes
 		// New: method-specific maps to avoid string concatenation
-		staticByMethod map[string]map[string]int   // method -> path -> endpoint index
-		prefixByMethod map[string]map[string][]int // method -> prefix -> []endpoint index
+		staticByMethod map[string]map[string]int // method -> path -> endpoint index
+		dynamic        map[string]*dynNode       // method -> segment tree of dynamic routes
+		endpoints      []*endpoint               // Registered endpoints in registration order
+		names          map[string]int            // Route name -> endpoint index
+		// method -> lowercase path -> endpoint indices (see findFoldPath)
+		staticFold map[string]map[string][]int
+		// Host route tables in registration order (nil when there are none)
+		hosts     []*hostTable
+		hostExact map[string]*hostTable // Host -> table for hosts without parameters
+		hostWild  []*hostTable          // Tables for hosts with parameters in priority order
+
+		// Routes sharing a method and pattern with routes with request matchers
+		primary  map[string]int // "METHOD/path" -> index of the indexed route
+		variants map[int][]int  // Indexed route -> routes in matching order
 	}
 
 	// endpoint contains route endpoint information
@@ -66,9 +109,20 @@ type (
 		chain       http.Handler // Handler with only endpoint middlewares applied
 		fullChain   http.Handler // Handler with all middlewares applied (used at runtime)
 		paramKeys   []string     // Parameter names (e.g., ["id", "name"])
-		pattern     string       // Route pattern (e.g., "/users/:id")
-		method      string       // HTTP method (e.g., "GET")
-		kind        nodeKind     // Node type (static/param/any)
+		pattern     string       // Route pattern (e.g., "/users/:id<int>")
+		// Pattern without parameter constraints (e.g., "/users/:id")
+		matchPattern string
+		// Parameter constraints in paramKeys order (nil when unconstrained)
+		constraints []func(string) bool
+		matchers    []requestMatcher // Request matchers (see MatchHeader)
+		score       int              // Priority among matching routes (see calculateScore)
+		name        string           // Route name for URL generation (optional)
+		meta        map[string]any   // Route metadata (see WithMeta)
+		// Pattern set as http.Request.Pattern ("[METHOD ][HOST]/path", see RoutePattern)
+		requestPattern string
+		host           string   // Host pattern (empty for the default host)
+		method         string   // HTTP method (e.g., "GET")
+		kind           nodeKind // Node type (static/param/any)
 	}
 
 	Middleware func(http.Handler) http.Handler
@@ -77,12 +131,17 @@ type (
 func newMux() *Mux {
 	m := &Mux{
 		doubleArray: newDoubleArrayTrie(),
-		endpoints:   make([]*endpoint, 0, initialEndpointsCap),
 		NotFound:    http.NotFound,
+		// Same response as http.ServeMux
+		methodNotAllowedHandler: methodNotAllowed,
+		automaticOptionsHandler: automaticOptions,
+		ImplicitHead:            true,
 	}
 
-	// Initialize notFoundChain with middleware
+	// Initialize notFoundChain, methodNotAllowedChain and optionsChain with middleware
 	m.notFoundChain = buildMiddlewareChain(m.NotFound, m.middlewares)
+	m.methodNotAllowedChain = buildMiddlewareChain(m.methodNotAllowedHandler, m.middlewares)
+	m.optionsChain = m.buildOptionsChain()
 
 	m.contextPool = sync.Pool{
 		New: func() interface{} {
@@ -116,20 +175,8 @@ func newMux() *Mux {
 func newDoubleArrayTrie() *doubleArrayTrie {
 	dat := &doubleArrayTrie{}
 
-	// Create initial data
-	initialData := &trieData{
-		base:           make([]int32, initialTrieSize),
-		check:          make([]int32, initialTrieSize),
-		routes:         make(map[string]int),
-		staticMap:      make(map[string]int),
-		prefixMap:      make(map[string][]int),
-		staticByMethod: make(map[string]map[string]int),
-		prefixByMethod: make(map[string]map[string][]int),
-	}
-	initialData.base[0] = 1
-
 	// Store initial data in atomic pointer
-	dat.data.Store(initialData)
+	dat.data.Store(newTrieData())
 
 	return dat
 }
@@ -145,7 +192,7 @@ func buildMiddlewareChain(handler http.Handler, middlewares []Middleware) http.H
 
 func isStaticPattern(v string) bool {
 	for i := 0; i < len(v); i++ {
-		if v[i] == ':' || v[i] == '*' {
+		if v[i] == ':' || v[i] == '*' || v[i] == '{' {
 			return false
 		}
 	}
@@ -193,16 +240,67 @@ func (m *Mux) Route(middlewares ...Middleware) *Route {
 	}
 }
 
+// With returns a Route applying options to the routes registered through it
+func (m *Mux) With(options ...RouteOption) *Route {
+	return &Route{
+		mux:     m,
+		prefix:  "",
+		options: options,
+	}
+}
+
 func (m *Mux) Use(middlewares ...Middleware) {
 	m.middlewares = append(m.middlewares, middlewares...)
 	// Rebuild chains immediately to avoid hot path check
 	m.rebuildMiddlewareChains()
 }
 
+// Pre adds middlewares that run for every request before the route is
+// looked up, in the order added and before the middlewares added with Use.
+// The route is looked up for the request they pass on, so they can rewrite
+// the path or host:
+//
+//	r.Pre(func(next http.Handler) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+//			req.URL.Path = strings.TrimPrefix(req.URL.Path, "/en")
+//			next.ServeHTTP(w, req)
+//		})
+//	})
+//
+// The route is not known yet, so URLParam and RouteInfo are empty in them.
+func (m *Mux) Pre(middlewares ...Middleware) {
+	m.preMiddlewares = append(m.preMiddlewares, middlewares...)
+	m.preChain = buildMiddlewareChain(http.HandlerFunc(m.route), m.preMiddlewares)
+}
+
 // SetNotFound sets custom 404 handler and rebuilds middleware chain
 func (m *Mux) SetNotFound(handler http.HandlerFunc) {
 	m.NotFound = handler
-	m.notFoundChain = buildMiddlewareChain(m.NotFound, m.middlewares)
+	m.notFoundSet = true
+	m.notFoundChain = m.buildNotFoundChain()
+}
+
+// SetMethodNotAllowed sets custom 405 handler and rebuilds middleware chain.
+// The Allow header is already set when the handler is called.
+func (m *Mux) SetMethodNotAllowed(handler http.HandlerFunc) {
+	m.methodNotAllowedHandler = handler
+	m.methodNotAllowedChain = buildMiddlewareChain(m.methodNotAllowedHandler, m.middlewares)
+}
+
+// SetAutomaticOptions sets custom handler for automatic OPTIONS responses and
+// rebuilds middleware chain. The Allow header is already set when the handler
+// is called. Passing nil disables automatic OPTIONS responses.
+func (m *Mux) SetAutomaticOptions(handler http.HandlerFunc) {
+	m.automaticOptionsHandler = handler
+	m.optionsChain = m.buildOptionsChain()
+}
+
+// Build automatic OPTIONS handler chain (nil when disabled)
+func (m *Mux) buildOptionsChain() http.Handler {
+	if m.automaticOptionsHandler == nil {
+		return nil
+	}
+	return buildMiddlewareChain(m.automaticOptionsHandler, m.middlewares)
 }
 
 func (m *Mux) Get(pattern string, handlerFunc http.HandlerFunc, middlewares ...Middleware) {
@@ -242,18 +340,93 @@ func (m *Mux) Trace(pattern string, handlerFunc http.HandlerFunc, middlewares ..
 }
 
 func (m *Mux) FileServer(pattern, root string, middlewares ...Middleware) {
-	contentsHandle(m, pattern, m.newFileServer(pattern, root).contents, middlewares...)
+	contentsHandle(m, pattern, m.newFileServer(root).contents, middlewares...)
 }
 
 func (m *Mux) Handle(method, pattern string, handler http.Handler, middlewares ...Middleware) {
+	m.handle(method, pattern, handler, middlewares, nil)
+}
+
+// TryHandle registers a route like Handle, but returns a *RouteError instead
+// of panicking for an invalid route, and instead of replacing or adding the
+// route for a duplicate or shadowed route:
+//
+//	if err := r.TryHandle(http.MethodGet, "/users/:name", h); errors.Is(err, bon.ErrShadowedRoute) {
+//		log.Printf("skipping route: %v", err)
+//	}
+//
+// Nothing is registered when an error is returned.
+func (m *Mux) TryHandle(method, pattern string, handler http.Handler, middlewares ...Middleware) error {
+	return m.tryHandle(method, pattern, handler, middlewares, nil, true)
+}
+
+// handle registers a route with route options
+func (m *Mux) handle(method, pattern string, handler http.Handler, middlewares []Middleware, options []RouteOption) {
+	if err := m.tryHandle(method, pattern, handler, middlewares, options, m.StrictRoutes); err != nil {
+		panic(err.Error())
+	}
+}
+
+// tryHandle stages a route with route options. Duplicate and shadowed
+// routes are rejected when strict is true.
+func (m *Mux) tryHandle(method, pattern string, handler http.Handler, middlewares []Middleware, options []RouteOption, strict bool) error {
+	ep, err := m.buildEndpoint(method, pattern, handler, middlewares, options)
+	if err != nil {
+		return err
+	}
+
+	m.doubleArray.mu.Lock()
+	defer m.doubleArray.mu.Unlock()
+
+	return m.stageLocked().insert(ep, strict)
+}
+
+// Compile builds the route table from the routes registered or removed
+// since it was last built, and publishes it with a single atomic swap.
+//
+// Registration only stages routes, so that the table is built once for any
+// number of them and registering n routes takes time linear in n. The table
+// is built on demand by the first request, or the first call of a method
+// reading the routes (Routes, URL...), after a change. Calling Compile after
+// registering the routes moves that work to startup.
+func (m *Mux) Compile() {
+	m.doubleArray.mu.Lock()
+	defer m.doubleArray.mu.Unlock()
+
+	m.doubleArray.compileLocked()
+}
+
+// stageLocked returns the staged route changes, starting them from the
+// current table (must be called with lock held)
+func (m *Mux) stageLocked() *txState {
+	dat := m.doubleArray
+	if dat.pending == nil {
+		dat.pending = newTxState(m, dat.data.Load())
+		dat.staged.Store(true)
+	}
+	return dat.pending
+}
+
+// newEndpoint validates the route and builds its endpoint, panicking for an
+// invalid route
+func (m *Mux) newEndpoint(method, pattern string, handler http.Handler, middlewares []Middleware, options []RouteOption) *endpoint {
+	ep, err := m.buildEndpoint(method, pattern, handler, middlewares, options)
+	if err != nil {
+		panic(err.Error())
+	}
+	return ep
+}
+
+// buildEndpoint validates the route and builds its endpoint
+func (m *Mux) buildEndpoint(method, pattern string, handler http.Handler, middlewares []Middleware, options []RouteOption) (*endpoint, error) {
 	// Validate HTTP method
 	if method == "" {
-		panic("bon: HTTP method cannot be empty")
+		return nil, invalidRouteError(method, pattern, fmt.Errorf("HTTP method cannot be empty"))
 	}
 
 	// Validate pattern
 	if err := validatePattern(pattern); err != nil {
-		panic("bon: " + err.Error())
+		return nil, invalidRouteError(method, pattern, err)
 	}
 
 	pattern = resolvePatternPrefix(pattern)
@@ -265,16 +438,25 @@ func (m *Mux) Handle(method, pattern string, handler http.Handler, middlewares .
 		pattern:     pattern,
 		method:      method,
 		kind:        nodeKindStatic,
+		// Static patterns have no constraints
+		matchPattern: pattern,
+	}
+
+	// Apply route options
+	for _, option := range options {
+		option(ep)
 	}
 
 	// Build middleware chain
 	ep.chain = buildMiddlewareChain(handler, middlewares)
 	ep.fullChain = buildMiddlewareChain(ep.chain, m.middlewares)
 
-	// Extract parameter keys
+	// Extract parameter keys and constraints
 	if !isStaticPattern(pattern) {
-		ep.paramKeys = extractParamKeys(pattern)
-		if containsWildcard(pattern) {
+		// Already validated above
+		ep.matchPattern, ep.constraints, _ = compileConstraints(pattern)
+		ep.paramKeys = extractParamKeys(ep.matchPattern)
+		if containsWildcard(ep.matchPattern) {
 			ep.kind = nodeKindAny
 		} else if len(ep.paramKeys) > 0 {
 			ep.kind = nodeKindParam
@@ -284,116 +466,242 @@ func (m *Mux) Handle(method, pattern string, handler http.Handler, middlewares .
 			m.maxParam = len(ep.paramKeys)
 		}
 	}
+	ep.score = calculateScore(ep)
+
+	ep.requestPattern = ep.host + ep.pattern
+	if ep.method != methodAny {
+		ep.requestPattern = ep.method + " " + ep.requestPattern
+	}
+
+	return ep, nil
+}
 
-	key := method + pattern
+// Remove unregisters the routes registered with method and pattern, with
+// any request matchers, and reports whether one existed. Requests already
+// being served by the routes complete normally. The routes of a host are
+// removed through the group returned by Host.
+func (m *Mux) Remove(method, pattern string) bool {
+	return m.remove("", method, resolvePatternPrefix(pattern))
+}
 
-	// Use atomic operation to handle route registration
+// Unregister the routes of host ("" for routes without host)
+func (m *Mux) remove(host, method, pattern string) bool {
 	m.doubleArray.mu.Lock()
 	defer m.doubleArray.mu.Unlock()
 
-	// Check if route already exists
-	currentData := m.doubleArray.data.Load()
-	if existingIdx, exists := currentData.routes[key]; exists {
-		// Replace existing route
-		m.endpoints[existingIdx] = ep
-		m.doubleArray.insertLocked(key, existingIdx)
-		return
-	}
+	return m.stageLocked().remove(routeKey(host, method, pattern))
+}
 
-	// Add new route
-	idx := len(m.endpoints)
-	m.endpoints = append(m.endpoints, ep)
-	m.doubleArray.insertLocked(key, idx)
+// Route key of endpoint
+func (ep *endpoint) key() string {
+	return ep.routeKey() + ep.matcherKey()
 }
 
-// insertLocked inserts into double array trie (must be called with lock held)
-func (dat *doubleArrayTrie) insertLocked(key string, index int) {
+// Route key of endpoint without its request matchers
+func (ep *endpoint) routeKey() string {
+	return routeKey(ep.host, ep.method, ep.pattern)
+}
 
-	// Get current data and create a copy
-	oldData := dat.data.Load()
-	newData := &trieData{
-		base:           make([]int32, len(oldData.base)),
-		check:          make([]int32, len(oldData.check)),
+// Route key of the routes of host, method and pattern
+func routeKey(host, method, pattern string) string {
+	if host != "" {
+		return host + " " + method + pattern
+	}
+	return method + pattern
+}
+
+// Route key of endpoint with parameter and wildcard names removed. Routes
+// with the same shape key match the same requests.
+func (ep *endpoint) shapeKey() string {
+	p := ep.pattern
+	var b strings.Builder
+	b.Grow(len(p))
+	for i := 0; i < len(p); {
+		switch p[i] {
+		case ':':
+			b.WriteByte(':')
+			i++
+			for i < len(p) && !isParamNameEnd(p[i]) && p[i] != '<' {
+				i++
+			}
+			if i < len(p) && p[i] == '<' {
+				// Keep the named constraint
+				end := i + strings.IndexByte(p[i:], '>') + 1
+				b.WriteString(p[i:end])
+				i = end
+			}
+		case '{':
+			// Keep the regular expression (which may contain braces)
+			depth, j := 0, i
+			for ; j < len(p); j++ {
+				if p[j] == '{' {
+					depth++
+				} else if p[j] == '}' {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+			}
+			b.WriteByte(':')
+			if _, expr, ok := strings.Cut(p[i+1:j], ":"); ok {
+				b.WriteString("{" + expr + "}")
+			}
+			i = j + 1
+		case '*':
+			b.WriteByte('*')
+			if isTrailingWildcard(p, i) {
+				i = len(p)
+			} else {
+				i++
+			}
+		default:
+			b.WriteByte(p[i])
+			i++
+		}
+	}
+
+	key := ep.method + b.String() + ep.matcherKey()
+	if ep.host != "" {
+		return ep.host + " " + key
+	}
+	return key
+}
+
+// Create empty trie data
+func newTrieData() *trieData {
+	data := &trieData{
+		base:           make([]int32, initialTrieSize),
+		check:          make([]int32, initialTrieSize),
 		routes:         make(map[string]int),
 		staticMap:      make(map[string]int),
-		prefixMap:      make(map[string][]int),
 		staticByMethod: make(map[string]map[string]int),
-		prefixByMethod: make(map[string]map[string][]int),
+		staticFold:     make(map[string]map[string][]int),
+		dynamic:        make(map[string]*dynNode),
+		endpoints:      make([]*endpoint, 0, initialEndpointsCap),
+		names:          make(map[string]int),
 	}
+	data.base[0] = 1
+	return data
+}
 
-	// Copy existing data
-	copy(newData.base, oldData.base)
-	copy(newData.check, oldData.check)
-	for k, v := range oldData.routes {
-		newData.routes[k] = v
-	}
-	for k, v := range oldData.staticMap {
-		newData.staticMap[k] = v
+// load returns the current trie data, building it first when route changes
+// are staged
+func (dat *doubleArrayTrie) load() *trieData {
+	if dat.staged.Load() {
+		dat.mu.Lock()
+		dat.compileLocked()
+		dat.mu.Unlock()
 	}
-	for k, v := range oldData.prefixMap {
-		newData.prefixMap[k] = append([]int{}, v...)
+	return dat.data.Load()
+}
+
+// compileLocked publishes trie data built with the staged route changes
+// (must be called with lock held)
+func (dat *doubleArrayTrie) compileLocked() {
+	if dat.pending == nil {
+		return
 	}
-	for method, paths := range oldData.staticByMethod {
-		newData.staticByMethod[method] = make(map[string]int)
-		for path, idx := range paths {
-			newData.staticByMethod[method][path] = idx
+
+	// Names were checked when staged
+	newData, _ := dat.buildData(dat.pending.live())
+
+	// Store new data atomically
+	dat.data.Store(newData)
+	dat.pending = nil
+	dat.staged.Store(false)
+}
+
+// buildData builds trie data from scratch for endpoints in order
+func (dat *doubleArrayTrie) buildData(endpoints []*endpoint) (*trieData, error) {
+	data := newTrieData()
+	for _, ep := range endpoints {
+		if err := dat.insertInData(data, ep); err != nil {
+			return nil, err
 		}
 	}
-	for method, prefixes := range oldData.prefixByMethod {
-		newData.prefixByMethod[method] = make(map[string][]int)
-		for prefix, indices := range prefixes {
-			newData.prefixByMethod[method][prefix] = append([]int{}, indices...)
+	return data, nil
+}
+
+// insertInData adds ep to data, replacing the route with the same key
+func (dat *doubleArrayTrie) insertInData(data *trieData, ep *endpoint) error {
+	key := ep.key()
+
+	// Check route name
+	if ep.name != "" {
+		if idx, ok := data.names[ep.name]; ok && data.endpoints[idx].key() != key {
+			return conflictError(ep, data.endpoints[idx], fmt.Errorf("%w: name %q", ErrDuplicateRoute, ep.name))
 		}
 	}
 
-	newData.routes[key] = index
+	// Host routes are looked up in the table of their host
+	if ep.host != "" {
+		dat.putInData(data.hostTable(ep.host).data, ep.method+ep.pattern+ep.matcherKey(), ep, true)
+	}
+	dat.putInData(data, key, ep, ep.host == "")
+	return nil
+}
+
+// putInData adds ep to data under key, replacing the route with the same key.
+// New routes are indexed for lookup when index is true.
+func (dat *doubleArrayTrie) putInData(data *trieData, key string, ep *endpoint, index bool) {
+	if idx, exists := data.routes[key]; exists {
+		// Replace existing route (indices are unchanged)
+		if name := data.endpoints[idx].name; name != "" {
+			delete(data.names, name)
+		}
+		data.endpoints[idx] = ep
+		if ep.name != "" {
+			data.names[ep.name] = idx
+		}
+		return
+	}
 
-	// Split method and path
-	methodEnd := strings.Index(key, "/")
-	if methodEnd == -1 {
-		// Store new data atomically
-		dat.data.Store(newData)
+	// Add new route
+	idx := len(data.endpoints)
+	data.endpoints = append(data.endpoints, ep)
+	if ep.name != "" {
+		data.names[ep.name] = idx
+	}
+	data.routes[key] = idx
+	if !index || data.addVariant(ep, idx) {
 		return
 	}
 
-	pattern := key[methodEnd:]
+	pattern := ep.pattern
 
 	// Register static routes in fast lookup map
 	if isStaticPattern(pattern) {
-		newData.staticMap[key] = index
+		data.staticMap[key] = idx
 
 		// Also register in method-specific map to avoid concatenation
-		method := key[:methodEnd]
-		if newData.staticByMethod[method] == nil {
-			newData.staticByMethod[method] = make(map[string]int)
+		if data.staticByMethod[ep.method] == nil {
+			data.staticByMethod[ep.method] = make(map[string]int)
 		}
-		newData.staticByMethod[method][pattern] = index
+		data.staticByMethod[ep.method][pattern] = idx
+		if data.staticFold[ep.method] == nil {
+			data.staticFold[ep.method] = make(map[string][]int)
+		}
+		lower := strings.ToLower(pattern)
+		data.staticFold[ep.method][lower] = append(data.staticFold[ep.method][lower], idx)
 
 		// Also register in double array trie
 		state := int32(0)
 		for _, ch := range []byte(key) {
-			nextState := dat.findNextStateInData(newData, state, ch)
+			nextState := dat.findNextStateInData(data, state, ch)
 			if nextState == -1 {
-				nextState = dat.allocateStateInData(newData, state, ch)
+				nextState = dat.allocateStateInData(data, state, ch)
 			}
 			state = nextState
 		}
 	} else {
-		// Manage dynamic routes by prefix
-		prefix := getStaticPrefix(pattern)
-		prefixKey := key[:methodEnd] + prefix
-		newData.prefixMap[prefixKey] = append(newData.prefixMap[prefixKey], index)
-
-		// Also register in method-specific prefix map
-		method := key[:methodEnd]
-		if newData.prefixByMethod[method] == nil {
-			newData.prefixByMethod[method] = make(map[string][]int)
+		// Index dynamic routes in the segment tree of the method
+		root := data.dynamic[ep.method]
+		if root == nil {
+			root = &dynNode{}
+			data.dynamic[ep.method] = root
 		}
-		newData.prefixByMethod[method][prefix] = append(newData.prefixByMethod[method][prefix], index)
+		root.insert(ep.matchPattern, 0, newDynRoute(idx, ep))
 	}
-
-	// Store new data atomically
-	dat.data.Store(newData)
 }
 
 // Find next state in specific trieData
@@ -490,129 +798,45 @@ func (dat *doubleArrayTrie) allocateStateInData(data *trieData, state int32, ch
 }
 
 func (m *Mux) lookup(r *http.Request) (*endpoint, *Context) {
-	method := r.Method
-	path := r.URL.Path
-
-	// 1. Fast lookup for static routes without allocation
 	// Get data atomically (lock-free read)
-	data := m.doubleArray.data.Load()
+	return m.lookupMethod(m.doubleArray.load(), r, r.Method, r.URL.Path)
+}
 
+// lookupMethod searches the route for method and path in data. Request
+// matchers of the routes are evaluated against r.
+func (m *Mux) lookupMethod(data *trieData, r *http.Request, method, path string) (*endpoint, *Context) {
+	// 1. Fast lookup for static routes without allocation
 	// Direct lookup without string concatenation
 	if methodMap, exists := data.staticByMethod[method]; exists {
 		if idx, exists := methodMap[path]; exists {
-			return m.endpoints[idx], nil
-		}
-	}
-
-	// 2. Search dynamic routes (prefix-based)
-	// Select best candidate
-	var bestMatch *endpoint
-	var bestCtx *Context
-	var bestScore int
-
-	// Track current context for cleanup
-	var currentCtx *Context
-
-	// Get parameter buffer from contextPool
-	paramsBufPtr := m.paramBufferPool.Get().(*[]string)
-	paramsBuf := (*paramsBufPtr)[:0]
-
-	// Prefix matching (process directly to avoid candidates slice)
-	processIndices := func(indices []int) {
-		for _, idx := range indices {
-			ep := m.endpoints[idx]
-			pattern := ep.pattern
-
-			// Ensure buffer has enough capacity for this route's parameters
-			needCap := len(ep.paramKeys)
-			if needCap > cap(paramsBuf) {
-				// Return current buffer to contextPool and get a larger one
-				*paramsBufPtr = paramsBuf[:0]
-				m.paramBufferPool.Put(paramsBufPtr)
-				// Create a new larger buffer
-				newBuf := make([]string, 0, needCap*2)
-				paramsBufPtr = &newBuf
-				paramsBuf = newBuf
-			}
-
-			// Reset buffer length but keep capacity
-			paramsBuf = paramsBuf[:cap(paramsBuf)]
-			// Use the same buffer for pattern matching
-			matched, paramCount := matchPatternOptimizedInPlace(pattern, path, paramsBuf)
-			if matched {
-				score := calculateScore(ep, paramCount)
-				if bestMatch == nil || score > bestScore {
-					bestMatch = ep
-					bestScore = score
-
-					if paramCount > 0 {
-						// Get new context only when needed
-						if currentCtx == nil {
-							currentCtx = m.contextPool.Get().(*Context)
-						}
-
-						// Setup parameters with capacity limit
-						// Pass the actual slice with param values
-						if !m.setupContextParams(currentCtx, paramsBuf[:paramCount], ep.paramKeys) {
-							// Too many parameters - skip this route
-							// Note: This will cause a 404 instead of an error
-							bestMatch = nil
-							bestScore = 0
-							bestCtx = nil
-							// Return context to contextPool
-							m.contextPool.Put(currentCtx.reset())
-							currentCtx = nil
-							continue
-						} else {
-							bestCtx = currentCtx
-						}
-					} else {
-						bestCtx = nil
-					}
-				}
+			if ep := data.choose(idx, r); ep != nil {
+				return ep, nil
 			}
 		}
 	}
 
-	// Root path check
-	if methodPrefixes, exists := data.prefixByMethod[method]; exists {
-		if indices, ok := methodPrefixes["/"]; ok {
-			processIndices(indices)
-		}
-
-		// Prefix matching without allocation
-		for i := 1; i < len(path); i++ {
-			if path[i] == '/' {
-				prefix := path[:i+1]
-				if indices, ok := methodPrefixes[prefix]; ok {
-					processIndices(indices)
-					// Early exit if we found a static route
-					if bestMatch != nil && bestMatch.kind == nodeKindStatic {
-						break
-					}
-				}
-			}
-		}
+	// 2. Search dynamic routes in the segment tree
+	root := data.dynamic[method]
+	if root == nil || path == "" || path[0] != '/' {
+		return nil, nil
 	}
+	search := dynSearch{m: m, data: data, r: r, path: path}
 
-	// Clean up parameter buffer
-	*paramsBufPtr = paramsBuf[:0]
-	m.paramBufferPool.Put(paramsBufPtr)
+	// Get parameter buffer from paramBufferPool
+	search.bufPtr = m.paramBufferPool.Get().(*[]string)
+	search.buf = (*search.bufPtr)[:0]
 
-	if bestMatch != nil {
-		// Return unused context to contextPool
-		if currentCtx != nil && currentCtx != bestCtx {
-			m.contextPool.Put(currentCtx.reset())
-		}
-		return bestMatch, bestCtx
-	}
+	search.walk(root, 0)
 
-	// Return context to contextPool if no match
-	if currentCtx != nil {
-		m.contextPool.Put(currentCtx.reset())
-	}
+	// Clean up parameter buffer
+	*search.bufPtr = search.buf[:0]
+	m.paramBufferPool.Put(search.bufPtr)
 
-	return nil, nil
+	// Return unused context to contextPool
+	if search.current != nil && search.current != search.ctx {
+		m.contextPool.Put(search.current.reset())
+	}
+	return search.best, search.ctx
 }
 
 // Setup context parameters
@@ -640,52 +864,192 @@ func (m *Mux) setupContextParams(ctx *Context, values []string, keys []string) b
 // Rebuild middleware chains
 func (m *Mux) rebuildMiddlewareChains() {
 	// Rebuild full chains for all endpoints
-	for _, ep := range m.endpoints {
+	for _, ep := range m.doubleArray.load().endpoints {
 		ep.fullChain = buildMiddlewareChain(ep.chain, m.middlewares)
 	}
 
-	// Rebuild 404 handler chain
-	m.notFoundChain = buildMiddlewareChain(m.NotFound, m.middlewares)
+	// Rebuild 404, 405 and automatic OPTIONS handler chains
+	m.notFoundChain = m.buildNotFoundChain()
+	m.methodNotAllowedChain = buildMiddlewareChain(m.methodNotAllowedHandler, m.middlewares)
+	m.optionsChain = m.buildOptionsChain()
+}
+
+// allowedMethods returns the sorted methods that have a route in data matching
+// path. The path "*" of an OPTIONS request (OPTIONS * HTTP/1.1) matches every
+// registered method.
+func (m *Mux) allowedMethods(data *trieData, r *http.Request, path string) []string {
+	var allowed []string
+	all := path == "*" && r.Method == http.MethodOptions
+	for method, paths := range data.staticByMethod {
+		if method == methodAny {
+			continue
+		}
+		if idx, exists := paths[path]; (exists && data.choose(idx, r) != nil) || all {
+			allowed = append(allowed, method)
+		}
+	}
+
+	for method := range data.dynamic {
+		if method == methodAny || slices.Contains(allowed, method) {
+			continue
+		}
+		if all {
+			allowed = append(allowed, method)
+			continue
+		}
+		ep, ctx := m.lookupMethod(data, r, method, path)
+		if ctx != nil {
+			m.contextPool.Put(ctx.reset())
+		}
+		if ep != nil {
+			allowed = append(allowed, method)
+		}
+	}
+
+	// HEAD is answered by the GET route when implicit HEAD is enabled
+	if m.ImplicitHead && slices.Contains(allowed, http.MethodGet) && !slices.Contains(allowed, http.MethodHead) {
+		allowed = append(allowed, http.MethodHead)
+	}
+
+	// OPTIONS is always answered when automatic OPTIONS responses are enabled
+	if len(allowed) > 0 && m.optionsChain != nil && !slices.Contains(allowed, http.MethodOptions) {
+		allowed = append(allowed, http.MethodOptions)
+	}
+
+	sort.Strings(allowed)
+	return allowed
+}
+
+// Default 405 handler
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+// Default automatic OPTIONS handler
+func automaticOptions(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if m.preChain != nil {
+		m.preChain.ServeHTTP(w, r)
+		return
+	}
+	m.route(w, r)
+}
+
+// route looks up the route for the request and handles it
+func (m *Mux) route(w http.ResponseWriter, r *http.Request) {
 	// Fast path: check static routes first without allocation
-	data := m.doubleArray.data.Load()
+	data := m.doubleArray.load()
+	path := r.URL.Path
+	if m.UseRawPath {
+		path = rawRoutePath(r.URL)
+		if m.EncodedSlashes == EncodedSlashReject && strings.Contains(path, "%2F") {
+			m.notFoundChain.ServeHTTP(w, r)
+			return
+		}
+	}
+
+	// Route with the routes of the matched host, if any
+	if data.hosts != nil {
+		if table, values := data.matchHost(r.Host); table != nil {
+			m.serveHost(w, r, path, table, values)
+			return
+		}
+	}
 
 	// Direct lookup without string concatenation
 	if methodMap, exists := data.staticByMethod[r.Method]; exists {
-		if idx, exists := methodMap[r.URL.Path]; exists {
+		if idx, exists := methodMap[path]; exists && data.variants == nil {
 			// Call static handler without defer for zero allocation
-			m.serveStatic(w, r, idx)
+			m.serveStatic(w, r, data.endpoints[idx])
 			return
 		}
 	}
 
 	// Fall back to full lookup for dynamic routes
-	m.serveHTTPDynamic(w, r)
+	m.serveHTTPDynamic(w, r, path, data, nil, nil)
+}
+
+// serveHost handles the request with the routes of the matched host
+func (m *Mux) serveHost(w http.ResponseWriter, r *http.Request, path string, table *hostTable, values []string) {
+	if values == nil {
+		// Same fast path as ServeHTTP for exact hosts
+		if methodMap, exists := table.data.staticByMethod[r.Method]; exists {
+			if idx, exists := methodMap[path]; exists && table.data.variants == nil {
+				m.serveStatic(w, r, table.data.endpoints[idx])
+				return
+			}
+		}
+	}
+	m.serveHTTPDynamic(w, r, path, table.data, table.keys, values)
 }
 
 // serveStatic handles static routes without panic recovery for zero allocation.
 // IMPORTANT: Use middleware.Recovery() for panic handling in production.
-func (m *Mux) serveStatic(w http.ResponseWriter, r *http.Request, idx int) {
-	m.endpoints[idx].fullChain.ServeHTTP(w, r)
+func (m *Mux) serveStatic(w http.ResponseWriter, r *http.Request, e *endpoint) {
+	if e.needsContext() || m.parent != nil {
+		m.serveEndpoint(w, r, e, nil)
+		return
+	}
+	if _, ok := e.handler.(ContextHandlerFunc); ok {
+		m.serveContextHandler(w, r, e, nil)
+		return
+	}
+	r.Pattern = e.requestPattern
+	e.fullChain.ServeHTTP(w, r)
 }
 
-func (m *Mux) serveHTTPDynamic(w http.ResponseWriter, r *http.Request) {
-	e, ctx := m.lookup(r)
+// serveHTTPDynamic handles the request for path with the routes in data.
+// hostKeys and hostValues are the parameters of the matched host.
+func (m *Mux) serveHTTPDynamic(w http.ResponseWriter, r *http.Request, path string, data *trieData, hostKeys, hostValues []string) {
+	e, ctx := m.lookupMethod(data, r, r.Method, path)
+
+	// Run the GET route without body for HEAD requests
+	head := false
+	if e == nil && r.Method == http.MethodHead && m.ImplicitHead {
+		e, ctx = m.lookupMethod(data, r, http.MethodGet, path)
+		head = e != nil
+	}
+
+	// Mounted handlers match any method. They compete with the route of the
+	// method on priority, which wins ties.
+	if data.staticByMethod[methodAny] != nil || data.dynamic[methodAny] != nil {
+		if anyEp, anyCtx := m.lookupMethod(data, r, methodAny, path); anyEp != nil && (e == nil || anyEp.score > e.score) {
+			if ctx != nil {
+				m.contextPool.Put(ctx.reset())
+			}
+			e, ctx, head = anyEp, anyCtx, false
+		} else if anyCtx != nil {
+			m.contextPool.Put(anyCtx.reset())
+		}
+	}
 
 	if e != nil {
-		if ctx != nil {
-			// We need to use WithContext for compatibility with middleware
-			// The sync.Map approach breaks when middleware modifies the request
-			r = ctx.WithContext(r)
-			e.fullChain.ServeHTTP(w, r)
+		if head {
+			hw := &headResponseWriter{ResponseWriter: w}
+			m.serveEndpoint(hw, r, e, m.withHostParams(ctx, hostKeys, hostValues))
+			hw.commit()
+			return
+		}
+		m.serveEndpoint(w, r, e, m.withHostParams(ctx, hostKeys, hostValues))
+		return
+	}
 
-			// Clean up context after use
-			m.contextPool.Put(ctx.reset())
-		} else {
-			e.fullChain.ServeHTTP(w, r)
+	// Redirect to an alternate form of the path that has a route
+	if m.redirectPath(w, r, data, path) {
+		return
+	}
+
+	// Automatic OPTIONS or 405 handler if the path matches under other methods
+	if allowed := m.allowedMethods(data, r, path); len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		if r.Method == http.MethodOptions && m.optionsChain != nil {
+			m.optionsChain.ServeHTTP(w, r)
+			return
 		}
+		m.methodNotAllowedChain.ServeHTTP(w, r)
 		return
 	}
 
@@ -693,6 +1057,80 @@ func (m *Mux) serveHTTPDynamic(w http.ResponseWriter, r *http.Request) {
 	m.notFoundChain.ServeHTTP(w, r)
 }
 
+// serveEndpoint runs the endpoint chain with the matched parameters
+func (m *Mux) serveEndpoint(w http.ResponseWriter, r *http.Request, e *endpoint, ctx *Context) {
+	if ctx != nil && m.UseRawPath {
+		ctx.unescapeParams(m.EncodedSlashes == EncodedSlashKeep)
+	}
+	if _, ok := e.handler.(ContextHandlerFunc); ok {
+		m.serveContextHandler(w, r, e, ctx)
+		return
+	}
+	if ctx == nil && (e.needsContext() || m.parent != nil) {
+		// Make the route name and metadata available to RouteInfo, and the Mux to Forward
+		ctx = m.contextPool.Get().(*Context)
+	}
+	if ctx != nil {
+		// Merge the parameters of the parent when mounted
+		if m.parent != nil {
+			ctx.inherit(r)
+		}
+		ctx.mux = m
+		ctx.route = e
+		ctx.pattern = m.requestPattern(r, e)
+		// We need to use WithContext for compatibility with middleware
+		// The sync.Map approach breaks when middleware modifies the request
+		r = ctx.WithContext(r)
+		r.Pattern = ctx.pattern
+		if m.PathValues {
+			ctx.setPathValues(r)
+		}
+		e.fullChain.ServeHTTP(w, r)
+
+		// Clean up context after use
+		m.contextPool.Put(ctx.reset())
+	} else {
+		r.Pattern = e.requestPattern
+		e.fullChain.ServeHTTP(w, r)
+	}
+}
+
+// serveContextHandler serves e, whose handler is a ContextHandlerFunc, with
+// the parameters in ctx (nil when there are none). ctx is not stored in the
+// request context: it is passed to the function directly when the route has
+// no middleware, and held for r through the middleware otherwise.
+func (m *Mux) serveContextHandler(w http.ResponseWriter, r *http.Request, e *endpoint, ctx *Context) {
+	if ctx == nil {
+		ctx = m.contextPool.Get().(*Context)
+	}
+	// Merge the parameters of the parent when mounted
+	if m.parent != nil {
+		ctx.inherit(r)
+	}
+	r.Pattern = m.requestPattern(r, e)
+	if m.PathValues {
+		ctx.setPathValues(r)
+	}
+	if f, ok := e.fullChain.(ContextHandlerFunc); ok {
+		f(w, r, ctx)
+	} else {
+		ctx.mux = m
+		ctx.route = e
+		ctx.pattern = r.Pattern
+		serveWithSlot(w, r, e.fullChain, ctx)
+	}
+
+	// Clean up context after use
+	m.contextPool.Put(ctx.reset())
+}
+
+// Serve r with handler, holding ctx for r meanwhile (see ContextHandlerFunc)
+func serveWithSlot(w http.ResponseWriter, r *http.Request, handler http.Handler, ctx *Context) {
+	prev := contextSlots.put(r, ctx)
+	defer contextSlots.restore(r, prev)
+	handler.ServeHTTP(w, r)
+}
+
 // Extract parameter keys
 func extractParamKeys(pattern string) []string {
 	var keys []string
@@ -700,16 +1138,29 @@ func extractParamKeys(pattern string) []string {
 		if pattern[i] == ':' {
 			start := i + 1
 			end := start
-			for end < len(pattern) && pattern[end] != '/' {
+			for end < len(pattern) && !isParamNameEnd(pattern[end]) {
 				end++
 			}
 			keys = append(keys, pattern[start:end])
 			i = end - 1
+		} else if pattern[i] == '*' && isTrailingWildcard(pattern, i) {
+			// Trailing wildcard captures the rest of the path
+			if name := pattern[i+1:]; name != "" {
+				keys = append(keys, name)
+			} else {
+				keys = append(keys, WildcardKey)
+			}
+			break
 		}
 	}
 	return keys
 }
 
+// Check if the wildcard at i is the last segment of pattern
+func isTrailingWildcard(pattern string, i int) bool {
+	return strings.IndexByte(pattern[i+1:], '/') == -1
+}
+
 // Check if pattern contains wildcard
 func containsWildcard(pattern string) bool {
 	for i := 0; i < len(pattern); i++ {
@@ -739,6 +1190,15 @@ func matchPatternOptimizedInPlace(pattern, path string, params []string) (bool,
 	for pi < plen && pj < pathlen {
 		switch pattern[pi] {
 		case '*':
+			// Trailing wildcard (optionally named) captures the rest of path
+			if isTrailingWildcard(pattern, pi) {
+				if paramCount < len(params) {
+					params[paramCount] = path[pj:]
+					return true, paramCount + 1
+				}
+				return false, 0
+			}
+
 			// Check if there's anything after the wildcard in pattern
 			if pi+1 < plen {
 				// Pattern continues after wildcard (e.g., "*/something")
@@ -768,19 +1228,51 @@ func matchPatternOptimizedInPlace(pattern, path string, params []string) (bool,
 				}
 				return false, 0
 			}
-			// Wildcard at end matches rest of path
-			return true, paramCount
 		case ':':
 			// Parameter extraction
-			pi++ // Skip ':'
+			shared := pattern[pi-1] != '/' // Static text precedes in the segment
+			pi++                           // Skip ':'
 			start := pj
-			// Skip parameter name in pattern
-			for pi < plen && pattern[pi] != '/' {
+			// Skip parameter name (and optional marker) in pattern
+			for pi < plen && !isParamNameEnd(pattern[pi]) {
+				pi++
+			}
+			if pi < plen && (pattern[pi] == '?' || pattern[pi] == paramEnd) {
 				pi++
 			}
 			// Extract value from path
-			for pj < pathlen && path[pj] != '/' {
-				pj++
+			segEnd := pj
+			for segEnd < pathlen && path[segEnd] != '/' {
+				segEnd++
+			}
+			if pi < plen && pattern[pi] != '/' {
+				// Static text follows in the same segment
+				textEnd := pi
+				for textEnd < plen && pattern[textEnd] != '/' && pattern[textEnd] != ':' && pattern[textEnd] != '*' {
+					textEnd++
+				}
+				text, segment := pattern[pi:textEnd], path[pj:segEnd]
+				if textEnd == plen || pattern[textEnd] == '/' {
+					// Text ending the segment must be its suffix
+					if !strings.HasSuffix(segment, text) {
+						return false, 0
+					}
+					pj = segEnd - len(text)
+				} else {
+					// Otherwise the parameter ends at its first occurrence
+					idx := strings.Index(segment, text)
+					if idx == -1 {
+						return false, 0
+					}
+					pj += idx
+				}
+				shared = true
+			} else {
+				pj = segEnd
+			}
+			// Parameter sharing its segment cannot be empty
+			if shared && pj == start {
+				return false, 0
 			}
 			if paramCount < len(params) {
 				params[paramCount] = path[start:pj]
@@ -804,36 +1296,67 @@ func matchPatternOptimizedInPlace(pattern, path string, params []string) (bool,
 		return true, paramCount
 	}
 
-	// Handle trailing wildcard
-	if pi < plen && pattern[pi] == '*' && pi == plen-1 {
-		return true, paramCount
+	// Handle omitted optional parameter
+	if pj == pathlen && isOptionalTail(pattern[pi:]) {
+		if paramCount < len(params) {
+			params[paramCount] = ""
+			return true, paramCount + 1
+		}
+		return false, 0
+	}
+
+	// Handle trailing wildcard matching the empty rest of path
+	if pi < plen && pattern[pi] == '*' && isTrailingWildcard(pattern, pi) {
+		if paramCount < len(params) {
+			params[paramCount] = ""
+			return true, paramCount + 1
+		}
+		return false, 0
 	}
 
 	return false, 0
 }
 
-// Calculate score
-func calculateScore(ep *endpoint, paramCount int) int {
+// Calculate score (higher wins among matching routes)
+func calculateScore(ep *endpoint) int {
 	if ep.kind == nodeKindStatic {
 		return 1000
 	}
 
 	// Calculate static length
 	score := 0
-	inParam := false
-	for i := 0; i < len(ep.pattern); i++ {
-		switch ep.pattern[i] {
+	pattern := ep.matchPattern
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
 		case ':':
-			inParam = true
+			// Parameter taking the whole segment costs more than one
+			// sharing its segment with static text
+			j := i + 1
+			for j < len(pattern) && !isParamNameEnd(pattern[j]) {
+				j++
+			}
+			if pattern[i-1] == '/' && (j == len(pattern) || pattern[j] == '/' || pattern[j] == '?') {
+				score -= 5
+			} else {
+				score--
+			}
+			// Optional parameter loses to the same required parameter
+			if j < len(pattern) && pattern[j] == '?' {
+				score--
+				j++
+			}
+			if j < len(pattern) && pattern[j] == paramEnd {
+				j++
+			}
+			i = j - 1
 		case '*':
-			inParam = false
-		case '/':
-			inParam = false
-			score++ // Count slashes as static content
-		default:
-			if !inParam {
-				score++
+			// Skip wildcard name
+			for i+1 < len(pattern) && pattern[i+1] != '/' {
+				i++
 			}
+		default:
+			// Count static content including slashes
+			score++
 		}
 	}
 
@@ -842,8 +1365,12 @@ func calculateScore(ep *endpoint, paramCount int) int {
 		score -= 100
 	}
 
-	// Penalty for parameter count
-	score -= paramCount * 5
+	// Constrained parameters win over unconstrained ones
+	for _, c := range ep.constraints {
+		if c != nil {
+			score++
+		}
+	}
 
 	return score
 }
@@ -851,10 +1378,14 @@ func calculateScore(ep *endpoint, paramCount int) int {
 // Get static prefix of pattern
 func getStaticPrefix(pattern string) string {
 	for i := 0; i < len(pattern); i++ {
-		if pattern[i] == ':' || pattern[i] == '*' {
+		if pattern[i] == ':' || pattern[i] == '*' || pattern[i] == '{' {
 			// Return until last slash
 			for j := i - 1; j >= 0; j-- {
 				if pattern[j] == '/' {
+					// Omitted optional parameter drops its segment
+					if j > 0 && isOptionalTail(pattern[j:]) {
+						return pattern[:strings.LastIndexByte(pattern[:j], '/')+1]
+					}
 					return pattern[:j+1]
 				}
 			}
@@ -898,12 +1429,19 @@ func validatePatternBasic(pattern string) error {
 	return nil
 }
 
-// Validate pattern parameters and wildcards
+// Validate pattern parameters, constraints and wildcards
 func validatePatternParams(pattern string) error {
+	// Validate constraints, then the plain :param form
+	pattern, _, err := compileConstraints(pattern)
+	if err != nil {
+		return err
+	}
+
 	var (
 		hasWildcard = false
 		inParam     = false
 		paramName   = ""
+		paramStart  = 0
 	)
 
 	for i := 1; i < len(pattern); i++ {
@@ -911,32 +1449,63 @@ func validatePatternParams(pattern string) error {
 
 		switch {
 		case ch == '*':
+			if inParam {
+				return fmt.Errorf("wildcard cannot follow parameter %q in its segment", paramName)
+			}
 			if hasWildcard {
 				return fmt.Errorf("pattern cannot contain multiple wildcards")
 			}
 			hasWildcard = true
 
+			// Only the trailing wildcard can be named
+			j := i + 1
+			for j < len(pattern) && pattern[j] != '/' {
+				if !isValidParamChar(pattern[j]) {
+					return fmt.Errorf("invalid character '%c' in wildcard name", pattern[j])
+				}
+				j++
+			}
+			if j > i+1 && j < len(pattern) {
+				return fmt.Errorf("named wildcard must be at the end of the pattern")
+			}
+			i = j - 1
+
 		case ch == ':':
 			if inParam {
-				return fmt.Errorf("invalid parameter syntax")
+				return fmt.Errorf("parameter name %q runs into ':' (a name ends at '/', '.', '?', a constraint or a closing brace, as in {%s})", paramName, paramName)
 			}
 			if i == len(pattern)-1 {
 				return fmt.Errorf("parameter name cannot be empty")
 			}
 			inParam = true
 			paramName = ""
+			paramStart = i
 
 		case inParam:
-			if ch == '/' {
+			switch {
+			case ch == '/' || ch == '.' || ch == paramEnd:
+				// End of segment, or static text in the same segment
 				if paramName == "" {
 					return fmt.Errorf("parameter name cannot be empty")
 				}
 				inParam = false
-			} else if !isValidParamChar(ch) {
+			case ch == '?':
+				if paramName == "" {
+					return fmt.Errorf("parameter name cannot be empty")
+				}
+				// Optional parameter must be the whole last segment
+				if i != len(pattern)-1 || pattern[paramStart-1] != '/' {
+					return fmt.Errorf("optional parameter must be the last segment")
+				}
+				inParam = false
+			case !isValidParamChar(ch):
 				return fmt.Errorf("invalid character '%c' in parameter name", ch)
-			} else {
+			default:
 				paramName += string(ch)
 			}
+
+		case ch == '?':
+			return fmt.Errorf("optional marker '?' must follow a parameter")
 		}
 	}
 
@@ -950,7 +1519,27 @@ func validatePatternParams(pattern string) error {
 // Check if character is valid for parameter name
 func isValidParamChar(ch byte) bool {
 	// Allow basic ASCII, underscore, and hyphen
-	// Forbid slash, colon, and asterisk
+	// Forbid slash, colon, asterisk, constraint delimiters, and the
+	// characters ending a parameter within a segment (dot, optional marker)
 	// Allow other characters (including Unicode)
-	return ch != '/' && ch != ':' && ch != '*' && ch != '\x00'
+	return ch != '/' && ch != ':' && ch != '*' && ch != '\x00' &&
+		ch != '<' && ch != '>' && ch != '{' && ch != '}' &&
+		ch != '.' && ch != '?'
+}
+
+// paramEnd ends a parameter name followed by static text in a compiled
+// pattern: "/img/:w<int>x:h<int>" compiles to "/img/:w\x00x:h". Patterns
+// cannot contain it.
+const paramEnd = '\x00'
+
+// Check if ch ends a parameter name
+func isParamNameEnd(ch byte) bool {
+	return ch == '/' || ch == '.' || ch == '?' || ch == ':' || ch == paramEnd
+}
+
+// Check if the rest of pattern is an optional parameter ("/:name?" or ":name?")
+func isOptionalTail(rest string) bool {
+	rest = strings.TrimPrefix(rest, "/")
+	return len(rest) > 2 && rest[0] == ':' && rest[len(rest)-1] == '?' &&
+		strings.IndexByte(rest, '/') == -1
 }
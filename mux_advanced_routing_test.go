@@ -221,9 +221,18 @@ func TestMuxHEADMethodHandling(t *testing.T) {
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
-	// Should return 404 without explicit HEAD handler
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Expected status 404 for HEAD without handler, got %d", w.Code)
+	// Without explicit HEAD handler, the GET route answers without body
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for HEAD without handler, got %d", w.Code)
+	}
+	if w.Header().Get("X-Custom-Header") != "test-value" {
+		t.Errorf("Expected custom header from GET handler")
+	}
+	if w.Header().Get("Content-Length") != "19" {
+		t.Errorf("Expected Content-Length 19, got %q", w.Header().Get("Content-Length"))
+	}
+	if w.Body.Len() > 0 {
+		t.Errorf("HEAD response should have empty body, got %d bytes", w.Body.Len())
 	}
 
 	// Register explicit HEAD handler
@@ -267,11 +276,11 @@ func TestMuxMethodCaseSensitivity(t *testing.T) {
 		wantBody string
 	}{
 		{"GET", http.StatusOK, "GET"},
-		{"get", http.StatusNotFound, ""},    // Case sensitive
-		{"Get", http.StatusNotFound, ""},    // Case sensitive
-		{"gEt", http.StatusNotFound, ""},    // Case sensitive
-		{"POST", http.StatusNotFound, ""},   // Different method
-		{"GETS", http.StatusNotFound, ""},   // Not a match
+		{"get", http.StatusMethodNotAllowed, ""},  // Case sensitive
+		{"Get", http.StatusMethodNotAllowed, ""},  // Case sensitive
+		{"gEt", http.StatusMethodNotAllowed, ""},  // Case sensitive
+		{"POST", http.StatusMethodNotAllowed, ""}, // Different method
+		{"GETS", http.StatusMethodNotAllowed, ""}, // Not a match
 	}
 
 	for _, tt := range tests {
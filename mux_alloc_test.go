@@ -260,3 +260,85 @@ func TestAllocationBreakdown(t *testing.T) {
 		})
 	})
 }
+
+// Test that ContextHandlerFunc routes do not allocate unless a middleware
+// copies the request
+func TestContextHandlerFuncAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not stable under the race detector")
+	}
+	var got [3]string
+	handler := ContextHandlerFunc(func(w http.ResponseWriter, r *http.Request, ctx *Context) {
+		got = [3]string{ctx.GetParam("id"), ctx.GetParam("post"), ctx.GetParam(WildcardKey)}
+	})
+	newRouter := func(middlewares ...Middleware) *Mux {
+		r := NewRouter()
+		r.Use(middlewares...)
+		r.Handle("GET", "/static", handler)
+		r.Handle("GET", "/users/:id", handler)
+		r.Handle("GET", "/users/:id<int>/posts/:post", handler)
+		r.Handle("GET", "/files/*", handler)
+		r.Compile()
+		return r
+	}
+	var seen string
+	passThrough := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen = URLParam(r, "id")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	tests := []struct {
+		path string
+		want [3]string
+	}{
+		{"/static", [3]string{}},
+		{"/users/abc", [3]string{"abc"}},
+		{"/users/1/posts/2", [3]string{"1", "2"}},
+		{"/files/a/b.txt", [3]string{2: "a/b.txt"}},
+	}
+
+	w := nullResponseWriter{}
+	for name, r := range map[string]*Mux{
+		"no middleware":           newRouter(),
+		"pass-through middleware": newRouter(passThrough),
+	} {
+		for _, tt := range tests {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			allocs := testing.AllocsPerRun(100, func() {
+				r.ServeHTTP(w, req)
+			})
+			if allocs != 0 {
+				t.Errorf("%s %s: expected 0 allocs/op, got %v", name, tt.path, allocs)
+			}
+			if got != tt.want {
+				t.Errorf("%s %s: expected params %q, got %q", name, tt.path, tt.want, got)
+			}
+		}
+	}
+
+	r := newRouter(passThrough)
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/abc", nil))
+	if seen != "abc" {
+		t.Errorf("Expected the middleware to read the id parameter, got %q", seen)
+	}
+}
+
+// Benchmark parameter route served to a ContextHandlerFunc
+func BenchmarkMuxContextHandlerFuncParam(b *testing.B) {
+	r := NewRouter()
+	r.Handle("GET", "/users/:id", ContextHandlerFunc(func(w http.ResponseWriter, r *http.Request, ctx *Context) {
+		_ = ctx.GetParam("id")
+	}))
+
+	req := httptest.NewRequest("GET", "/users/123", nil)
+	w := nullResponseWriter{}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(w, req)
+	}
+}
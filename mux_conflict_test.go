@@ -0,0 +1,121 @@
+package bon
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// Test TryHandle errors for invalid, duplicate and shadowed routes
+func TestMuxTryHandle(t *testing.T) {
+	r := NewRouter()
+	h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+
+	for _, pattern := range []string{
+		"/users/:id",
+		"/users/:id<int>",
+		"/files/{path:[a-z]+}",
+		"/assets/*",
+		"/docs/:lang?",
+	} {
+		if err := r.TryHandle("GET", pattern, h); err != nil {
+			t.Fatalf("TryHandle(%q): unexpected error %v", pattern, err)
+		}
+	}
+	if err := r.With(MatchHeader("Accept", "text/csv")).TryHandle("GET", "/users/:id", h); err != nil {
+		t.Fatalf("Route with matchers: unexpected error %v", err)
+	}
+
+	tests := []struct {
+		method   string
+		pattern  string
+		want     error
+		conflict string
+	}{
+		{"", "/x", ErrInvalidRoute, ""},
+		{"GET", "users", ErrInvalidRoute, ""},
+		{"GET", "/a/:id<nope>", ErrInvalidRoute, ""},
+		{"GET", "/users/:id", ErrDuplicateRoute, "GET /users/:id"},
+		{"GET", "/users/:name", ErrShadowedRoute, "GET /users/:id"},
+		{"GET", "/users/{name}", ErrShadowedRoute, "GET /users/:id"},
+		{"GET", "/users/:uid<int>", ErrShadowedRoute, "GET /users/:id<int>"},
+		{"GET", "/files/{name:[a-z]+}", ErrShadowedRoute, "GET /files/{path:[a-z]+}"},
+		{"GET", "/assets/*filepath", ErrShadowedRoute, "GET /assets/*"},
+		{"GET", "/docs/:locale?", ErrShadowedRoute, "GET /docs/:lang?"},
+	}
+
+	for _, tt := range tests {
+		err := r.TryHandle(tt.method, tt.pattern, h)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("TryHandle(%q, %q): expected %v, got %v", tt.method, tt.pattern, tt.want, err)
+			continue
+		}
+		var routeErr *RouteError
+		if !errors.As(err, &routeErr) {
+			t.Errorf("TryHandle(%q, %q): expected *RouteError, got %T", tt.method, tt.pattern, err)
+			continue
+		}
+		if routeErr.Pattern != tt.pattern || routeErr.Conflict != tt.conflict {
+			t.Errorf("TryHandle(%q, %q): unexpected error fields %+v", tt.method, tt.pattern, routeErr)
+		}
+	}
+
+	// Nothing was registered for the failed routes
+	if n := len(r.doubleArray.load().endpoints); n != 6 {
+		t.Errorf("Expected 6 routes, got %d", n)
+	}
+
+	// Routes differing in constraints, method or host are not conflicts
+	if err := r.TryHandle("POST", "/users/:name", h); err != nil {
+		t.Errorf("Other method: unexpected error %v", err)
+	}
+	if err := r.Host("api.example.com").TryHandle("GET", "/users/:name", h); err != nil {
+		t.Errorf("Host route: unexpected error %v", err)
+	}
+	if err := r.Group("/users").TryHandle("GET", "/:id<uuid>", h); err != nil {
+		t.Errorf("Other constraint: unexpected error %v", err)
+	}
+	if err := r.Route().TryHandle("GET", "/users/:id<int>", h); !errors.Is(err, ErrDuplicateRoute) {
+		t.Errorf("Route: expected duplicate route, got %v", err)
+	}
+}
+
+// Test strict mode and route names
+func TestMuxStrictRoutes(t *testing.T) {
+	h := func(w http.ResponseWriter, req *http.Request) {}
+
+	r := NewRouter()
+	r.With(WithName("user")).Get("/users/:id", h)
+	if err := r.With(WithName("user")).TryHandle("GET", "/members/:id", http.HandlerFunc(h)); !errors.Is(err, ErrDuplicateRoute) {
+		t.Errorf("Expected duplicate route name, got %v", err)
+	}
+
+	// Handle replaces duplicates by default
+	r.Get("/users/:id", h)
+	r.Get("/users/:name", h)
+
+	r.StrictRoutes = true
+	for _, pattern := range []string{"/users/:id", "/users/:key"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for %q in strict mode", pattern)
+				}
+			}()
+			r.Get(pattern, h)
+		}()
+	}
+
+	// Transactions check the staged routes
+	err := r.Update(func(tx *Tx) error {
+		tx.Remove("GET", "/users/:id")
+		if err := tx.TryHandle("GET", "/users/:uid", http.HandlerFunc(h)); !errors.Is(err, ErrShadowedRoute) {
+			t.Errorf("Expected shadowed by /users/:name, got %v", err)
+		}
+		tx.Remove("GET", "/users/:name")
+		return tx.TryHandle("GET", "/users/:uid", http.HandlerFunc(h))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
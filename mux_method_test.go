@@ -0,0 +1,242 @@
+package bon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test 405 Method Not Allowed responses
+func TestMuxMethodNotAllowed(t *testing.T) {
+	r := NewRouter()
+
+	r.Get("/users", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("list"))
+	})
+	r.Post("/users", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("create"))
+	})
+	r.Get("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("get"))
+	})
+	r.Delete("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("delete"))
+	})
+	r.Put("/files/*", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("upload"))
+	})
+
+	tests := []struct {
+		method    string
+		path      string
+		wantCode  int
+		wantAllow string
+	}{
+		{"GET", "/users", http.StatusOK, ""},
+		{"PUT", "/users", http.StatusMethodNotAllowed, "GET, HEAD, OPTIONS, POST"},
+		{"PATCH", "/users/123", http.StatusMethodNotAllowed, "DELETE, GET, HEAD, OPTIONS"},
+		{"GET", "/files/a/b.txt", http.StatusMethodNotAllowed, "OPTIONS, PUT"},
+		{"GET", "/unknown", http.StatusNotFound, ""},
+		{"POST", "/users/123/posts", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.wantCode, w.Code)
+		}
+		if got := w.Header().Get("Allow"); got != tt.wantAllow {
+			t.Errorf("%s %s: expected Allow %q, got %q", tt.method, tt.path, tt.wantAllow, got)
+		}
+	}
+}
+
+// Test custom 405 handler runs through global middleware
+func TestMuxSetMethodNotAllowed(t *testing.T) {
+	r := NewRouter()
+	r.Use(WriteMiddleware("M"))
+
+	r.Get("/resource", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("GET"))
+	})
+	r.SetMethodNotAllowed(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("allow=" + w.Header().Get("Allow")))
+	})
+
+	if err := VerifyExtended(r, []*Want{
+		{"GET:/resource", 200, "MGET"},
+		{"POST:/resource", 200, "Mallow=GET, HEAD, OPTIONS"},
+		{"POST:/missing", 200, "M404 page not found\n"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test automatic OPTIONS responses
+func TestMuxAutomaticOptions(t *testing.T) {
+	r := NewRouter()
+
+	r.Get("/users/:id", func(w http.ResponseWriter, req *http.Request) {})
+	r.Put("/users/:id", func(w http.ResponseWriter, req *http.Request) {})
+	r.Post("/items", func(w http.ResponseWriter, req *http.Request) {})
+	r.Options("/items", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Options", "explicit")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		path         string
+		wantCode     int
+		wantAllow    string
+		wantExplicit bool
+	}{
+		{"/users/1", http.StatusNoContent, "GET, HEAD, OPTIONS, PUT", false},
+		{"/items", http.StatusOK, "", true},
+		{"/missing", http.StatusNotFound, "", false},
+		{"*", http.StatusNoContent, "GET, HEAD, OPTIONS, POST, PUT", false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("OPTIONS", "/", nil)
+		req.URL.Path = tt.path
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("OPTIONS %s: expected status %d, got %d", tt.path, tt.wantCode, w.Code)
+		}
+		if got := w.Header().Get("Allow"); got != tt.wantAllow {
+			t.Errorf("OPTIONS %s: expected Allow %q, got %q", tt.path, tt.wantAllow, got)
+		}
+		if explicit := w.Header().Get("X-Options") == "explicit"; explicit != tt.wantExplicit {
+			t.Errorf("OPTIONS %s: expected explicit handler=%v", tt.path, tt.wantExplicit)
+		}
+	}
+
+	// "*" only stands for every path in OPTIONS requests
+	req := httptest.NewRequest("GET", "/", nil)
+	req.URL.Path = "*"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound || w.Header().Get("Allow") != "" {
+		t.Errorf("GET *: expected 404 without Allow, got %d %q", w.Code, w.Header().Get("Allow"))
+	}
+}
+
+// Test customizing and disabling automatic OPTIONS responses
+func TestMuxSetAutomaticOptions(t *testing.T) {
+	r := NewRouter()
+	r.Get("/resource", func(w http.ResponseWriter, req *http.Request) {})
+
+	r.SetAutomaticOptions(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Access-Control-Allow-Methods", w.Header().Get("Allow"))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("OPTIONS", "/resource", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 from custom handler, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Methods"); got != "GET, HEAD, OPTIONS" {
+		t.Errorf("Expected Access-Control-Allow-Methods %q, got %q", "GET, HEAD, OPTIONS", got)
+	}
+
+	// Disabled: OPTIONS is just another unregistered method
+	r.SetAutomaticOptions(nil)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 when disabled, got %d", w.Code)
+	}
+	if got := w.Header().Get("Allow"); got != "GET, HEAD" {
+		t.Errorf("Expected Allow %q, got %q", "GET, HEAD", got)
+	}
+}
+
+// Test implicit HEAD handling for GET routes
+func TestMuxImplicitHead(t *testing.T) {
+	r := NewRouter()
+
+	r.Get("/static", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Method", req.Method)
+		_, _ = w.Write([]byte("static body"))
+	})
+	r.Get("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("user " + URLParam(req, "id")))
+	})
+	r.Get("/empty", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.Get("/explicit", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("get"))
+	})
+	r.Head("/explicit", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Handler", "head")
+	})
+
+	tests := []struct {
+		path         string
+		wantCode     int
+		wantLength   string
+		wantXMethod  string
+		wantXHandler string
+	}{
+		{"/static", http.StatusOK, "11", "HEAD", ""},
+		{"/users/42", http.StatusAccepted, "7", "", ""},
+		{"/empty", http.StatusNoContent, "", "", ""},
+		{"/explicit", http.StatusOK, "", "", "head"},
+		{"/missing", http.StatusNotFound, "19", "", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("HEAD", tt.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("HEAD %s: expected status %d, got %d", tt.path, tt.wantCode, w.Code)
+		}
+		if w.Code != http.StatusNotFound && w.Body.Len() > 0 {
+			t.Errorf("HEAD %s: expected empty body, got %q", tt.path, w.Body.String())
+		}
+		if got := w.Header().Get("Content-Length"); tt.wantCode != http.StatusNotFound && got != tt.wantLength {
+			t.Errorf("HEAD %s: expected Content-Length %q, got %q", tt.path, tt.wantLength, got)
+		}
+		if got := w.Header().Get("X-Method"); got != tt.wantXMethod {
+			t.Errorf("HEAD %s: expected X-Method %q, got %q", tt.path, tt.wantXMethod, got)
+		}
+		if got := w.Header().Get("X-Handler"); got != tt.wantXHandler {
+			t.Errorf("HEAD %s: expected X-Handler %q, got %q", tt.path, tt.wantXHandler, got)
+		}
+	}
+}
+
+// Test disabling implicit HEAD handling
+func TestMuxImplicitHeadDisabled(t *testing.T) {
+	r := NewRouter()
+	r.ImplicitHead = false
+
+	r.Get("/resource", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("get"))
+	})
+
+	req := httptest.NewRequest("HEAD", "/resource", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+	if got := w.Header().Get("Allow"); got != "GET, OPTIONS" {
+		t.Errorf("Expected Allow %q, got %q", "GET, OPTIONS", got)
+	}
+}
@@ -62,6 +62,18 @@ func BenchmarkRouteLookup(b *testing.B) {
 			requestPath: "/api/v1/dynamic/123",
 			routeType:   "mixed",
 		},
+		{
+			name:        "shared_prefix_100_routes",
+			numRoutes:   100,
+			requestPath: "/api/acme/r1/123",
+			routeType:   "shared",
+		},
+		{
+			name:        "shared_prefix_3000_routes",
+			numRoutes:   3000,
+			requestPath: "/api/acme/r1/123",
+			routeType:   "shared",
+		},
 	}
 
 	for _, tt := range tests {
@@ -98,6 +110,14 @@ func BenchmarkRouteLookup(b *testing.B) {
 						w.WriteHeader(http.StatusOK)
 					})
 				}
+			case "shared":
+				// Parameterized routes under the same static prefix
+				for i := 0; i < tt.numRoutes; i++ {
+					path := fmt.Sprintf("/api/:tenant/r%d/:id", i)
+					m.Get(path, func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(http.StatusOK)
+					})
+				}
 			}
 			
 			// Add the actual route that will be hit
@@ -255,4 +275,53 @@ func BenchmarkComplexRouting(b *testing.B) {
 		req := reqs[i%len(reqs)]
 		m.ServeHTTP(w, req)
 	}
-}
\ No newline at end of file
+}
+
+// Benchmark registering and compiling route tables of growing size
+func BenchmarkRouteRegistration(b *testing.B) {
+	h := func(w http.ResponseWriter, r *http.Request) {}
+
+	// Cost per route stays flat as the table grows
+	for _, numRoutes := range []int{1000, 5000, 20000} {
+		b.Run(fmt.Sprintf("%d_routes", numRoutes), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				m := NewRouter()
+				for j := 0; j < numRoutes; j++ {
+					switch j % 4 {
+					case 0:
+						m.Get(fmt.Sprintf("/api/v1/resource%d", j), h)
+					case 1:
+						m.Post(fmt.Sprintf("/api/v1/resource%d/:id", j), h)
+					case 2:
+						m.Get(fmt.Sprintf("/api/v1/tenants/:tenant/resource%d/:id<int>", j), h)
+					default:
+						m.Get(fmt.Sprintf("/static/resource%d/*", j), h)
+					}
+				}
+				m.Compile()
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*numRoutes), "ns/route")
+		})
+	}
+}
+
+// Benchmark misses with RedirectCaseInsensitive, which only try the routes
+// whose segments fit the path
+func BenchmarkCaseInsensitiveMiss(b *testing.B) {
+	r := NewRouter()
+	r.RedirectCaseInsensitive = true
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	for i := 0; i < 1000; i++ {
+		r.Get(fmt.Sprintf("/static%d/page", i), handler)
+		r.Get(fmt.Sprintf("/api%d/users/:id", i), handler)
+	}
+	req := httptest.NewRequest("GET", "/missing/path", nil)
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(w, req)
+	}
+}
@@ -129,14 +129,13 @@ func TestMuxAdvancedParameterPatterns(t *testing.T) {
 			params:      map[string]string{"用户ID": "12345"},
 			shouldMatch: true,
 		},
-		// Note: Pattern with multiple parameters in single segment not supported
-		// {
-		// 	name:        "Parameter with dots",
-		// 	pattern:     "/files/:filename.:ext",
-		// 	requestPath: "/files/document.pdf",
-		// 	params:      map[string]string{"filename": "document", "ext": "pdf"},
-		// 	shouldMatch: true,
-		// },
+		{
+			name:        "Parameter with dots",
+			pattern:     "/files/:filename.:ext",
+			requestPath: "/files/document.pdf",
+			params:      map[string]string{"filename": "document", "ext": "pdf"},
+			shouldMatch: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -242,9 +241,15 @@ func TestMuxMethodSpecificBehaviors(t *testing.T) {
 		w := httptest.NewRecorder()
 		r.ServeHTTP(w, req)
 
-		// Without explicit HEAD handler, should return 404
-		if w.Code != http.StatusNotFound {
-			t.Errorf("Expected status 404 for HEAD without handler, got %d", w.Code)
+		// Without explicit HEAD handler, the GET route answers without body
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 for HEAD without handler, got %d", w.Code)
+		}
+		if w.Header().Get("X-Method") != "GET" {
+			t.Errorf("Expected X-Method GET, got %s", w.Header().Get("X-Method"))
+		}
+		if w.Body.Len() > 0 {
+			t.Errorf("HEAD response should have empty body, got %d bytes", w.Body.Len())
 		}
 	})
 
@@ -278,9 +283,12 @@ func TestMuxMethodSpecificBehaviors(t *testing.T) {
 		w := httptest.NewRecorder()
 		r.ServeHTTP(w, req)
 
-		// Without specific OPTIONS handler, should return 404
-		if w.Code != http.StatusNotFound {
-			t.Errorf("Expected status 404 for OPTIONS without handler, got %d", w.Code)
+		// Without specific OPTIONS handler, should be answered automatically
+		if w.Code != http.StatusNoContent {
+			t.Errorf("Expected status 204 for OPTIONS without handler, got %d", w.Code)
+		}
+		if got := w.Header().Get("Allow"); got != "GET, HEAD, OPTIONS, POST" {
+			t.Errorf("Expected Allow %q, got %q", "GET, HEAD, OPTIONS, POST", got)
 		}
 	})
 
@@ -290,9 +298,12 @@ func TestMuxMethodSpecificBehaviors(t *testing.T) {
 		w := httptest.NewRecorder()
 		r.ServeHTTP(w, req)
 
-		// Should return 404 (not 405 Method Not Allowed)
-		if w.Code != http.StatusNotFound {
-			t.Errorf("Expected status 404 for unsupported method, got %d", w.Code)
+		// Should return 405 with the registered methods
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected status 405 for unsupported method, got %d", w.Code)
+		}
+		if got := w.Header().Get("Allow"); got != "GET, HEAD, OPTIONS, POST" {
+			t.Errorf("Expected Allow %q, got %q", "GET, HEAD, OPTIONS, POST", got)
 		}
 	})
 }
@@ -351,6 +362,120 @@ func TestMuxDeepNesting(t *testing.T) {
 	}
 }
 
+// Test parameters sharing a segment with static text
+func TestMuxMidSegmentParameters(t *testing.T) {
+	r := NewRouter()
+
+	r.Get("/files/:name.:ext", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(URLParam(req, "name") + "|" + URLParam(req, "ext")))
+	})
+	r.Get("/api/users/:id.json", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("json " + URLParam(req, "id")))
+	})
+	r.Get("/api/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("user " + URLParam(req, "id")))
+	})
+	r.Get("/v:version/status", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("version " + URLParam(req, "version")))
+	})
+	r.Get("/@:handle", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("handle " + URLParam(req, "handle")))
+	})
+	r.Get("/img/:size<int>.png", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("size " + URLParam(req, "size")))
+	})
+	// A constraint or braces end the name before other static text
+	r.Get("/img/:w<int>x:h<int>", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(URLParam(req, "w") + "x" + URLParam(req, "h")))
+	})
+	r.Get("/d/{from}-{to}/days", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(URLParam(req, "from") + " to " + URLParam(req, "to")))
+	})
+
+	if err := Verify(r, []*Want{
+		{"/files/report.pdf", 200, "report|pdf"},
+		// The name ends at the first dot
+		{"/files/archive.tar.gz", 200, "archive|tar.gz"},
+		{"/files/report", 404, "404 page not found\n"},
+		{"/files/.pdf", 404, "404 page not found\n"},
+		// Static suffix beats the whole-segment parameter
+		{"/api/users/42.json", 200, "json 42"},
+		{"/api/users/a.b.json", 200, "json a.b"},
+		{"/api/users/42", 200, "user 42"},
+		{"/v2/status", 200, "version 2"},
+		{"/v/status", 404, "404 page not found\n"},
+		{"/@gopher", 200, "handle gopher"},
+		{"/img/640.png", 200, "size 640"},
+		{"/img/wide.png", 404, "404 page not found\n"},
+		{"/img/640x480", 200, "640x480"},
+		{"/img/640x", 404, "404 page not found\n"},
+		{"/img/wxh", 404, "404 page not found\n"},
+		{"/d/mon-fri/days", 200, "mon to fri"},
+		{"/d/mon-/days", 404, "404 page not found\n"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test optional trailing parameters
+func TestMuxOptionalParameters(t *testing.T) {
+	r := NewRouter()
+
+	r.Get("/docs/:lang?", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("docs [" + URLParam(req, "lang") + "]"))
+	})
+	r.Get("/docs/latest", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("latest"))
+	})
+	r.Get("/users/:id/posts/:page<int>?", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(URLParam(req, "id") + " page [" + URLParam(req, "page") + "]"))
+	})
+	r.Get("/users/:id/posts/:slug", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("slug " + URLParam(req, "slug")))
+	})
+
+	if err := Verify(r, []*Want{
+		{"/docs", 200, "docs []"},
+		{"/docs/", 200, "docs []"},
+		{"/docs/en", 200, "docs [en]"},
+		{"/docs/latest", 200, "latest"},
+		{"/docs/en/more", 404, "404 page not found\n"},
+		{"/users/7/posts", 200, "7 page []"},
+		{"/users/7/posts/2", 200, "7 page [2]"},
+		// Required parameter beats the optional one, failed constraint falls through
+		{"/users/7/posts/hello", 200, "slug hello"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test invalid mid-segment and optional parameter patterns
+func TestMuxParameterShapeInvalid(t *testing.T) {
+	patterns := []string{
+		"/:a:b",
+		"/d/:from-:to",
+		"/img/{w}:h",
+		"/img/:w<int>*",
+		"/x:lang?",
+		"/:a?/b",
+		"/:a?.json",
+		"/a?",
+		"/:?",
+		"/files/*name?",
+	}
+
+	for _, pattern := range patterns {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for pattern %q", pattern)
+				}
+			}()
+			NewRouter().Get(pattern, func(w http.ResponseWriter, req *http.Request) {})
+		}()
+	}
+}
+
 // Test route priority edge cases
 func TestMuxRoutePriorityEdgeCases(t *testing.T) {
 	tests := []struct {
@@ -447,8 +447,8 @@ func TestMuxErrorCases(t *testing.T) {
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
-	if w.Code != http.StatusNotFound {
-		t.Errorf("POST /users/123: got status %d, want %d", w.Code, http.StatusNotFound)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /users/123: got status %d, want %d", w.Code, http.StatusMethodNotAllowed)
 	}
 }
 
@@ -0,0 +1,275 @@
+package bon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// Test removing routes at runtime
+func TestMuxRemove(t *testing.T) {
+	r := NewRouter()
+
+	r.Get("/a", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("a"))
+	})
+	r.With(WithName("user")).Get("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("user " + URLParam(req, "id")))
+	})
+	r.Post("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("update"))
+	})
+	r.Get("/b", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("b"))
+	})
+
+	if !r.Remove("GET", "/a") {
+		t.Error("Expected GET /a to be removed")
+	}
+	if !r.Remove("GET", "users/:id") {
+		t.Error("Expected GET /users/:id to be removed")
+	}
+	if r.Remove("GET", "/a") {
+		t.Error("Expected second removal of GET /a to report false")
+	}
+	if r.Remove("DELETE", "/b") {
+		t.Error("Expected removal of unregistered method to report false")
+	}
+
+	if err := VerifyExtended(r, []*Want{
+		{"/a", 404, "404 page not found\n"},
+		{"/b", 200, "b"},
+		{"/users/1", 405, "Method Not Allowed\n"},
+		{"POST:/users/1", 200, "update"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.URL("user", "id", "1"); err == nil {
+		t.Error("Expected removed route name to be released")
+	}
+
+	// The name can be reused once the route is removed
+	r.With(WithName("user")).Get("/members/:id", func(w http.ResponseWriter, req *http.Request) {})
+	if got, err := r.URL("user", "id", "1"); err != nil || got != "/members/1" {
+		t.Errorf("URL(user) = %q, %v, want %q", got, err, "/members/1")
+	}
+}
+
+// Test applying many changes with one update
+func TestMuxUpdate(t *testing.T) {
+	r := NewRouter()
+
+	r.Get("/tenants/acme/*", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("acme"))
+	})
+	r.Get("/health", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	err := r.Update(func(tx *Tx) error {
+		if !tx.Remove("GET", "/tenants/acme/*") {
+			t.Error("Expected GET /tenants/acme/* to be removed")
+		}
+		tx.Get("/tenants/globex/*", func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte("globex"))
+		})
+		tx.With(WithName("tenant")).Get("/tenants/:tenant", func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte("tenant " + URLParam(req, "tenant")))
+		})
+		// Replaced within the same update
+		tx.Get("/health", func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte("healthy"))
+		})
+		// Added and removed within the same update
+		tx.Post("/tmp", func(w http.ResponseWriter, req *http.Request) {})
+		tx.Remove("POST", "/tmp")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyExtended(r, []*Want{
+		{"/tenants/acme/x", 404, "404 page not found\n"},
+		{"/tenants/globex/x", 200, "globex"},
+		{"/tenants/initech", 200, "tenant initech"},
+		{"/health", 200, "healthy"},
+		{"POST:/tmp", 404, "404 page not found\n"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := r.URL("tenant", "tenant", "acme"); err != nil || got != "/tenants/acme" {
+		t.Errorf("URL(tenant) = %q, %v, want %q", got, err, "/tenants/acme")
+	}
+}
+
+// Test failed updates publish nothing
+func TestMuxUpdateRollback(t *testing.T) {
+	r := NewRouter()
+	r.Get("/keep", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("keep"))
+	})
+
+	errAbort := errors.New("abort")
+	err := r.Update(func(tx *Tx) error {
+		tx.Remove("GET", "/keep")
+		tx.Get("/new", func(w http.ResponseWriter, req *http.Request) {})
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("Expected abort error, got %v", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic for invalid pattern")
+			}
+		}()
+		_ = r.Update(func(tx *Tx) error {
+			tx.Remove("GET", "/keep")
+			tx.Get("/bad/:", func(w http.ResponseWriter, req *http.Request) {})
+			return nil
+		})
+	}()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic for duplicate route name")
+			}
+		}()
+		_ = r.Update(func(tx *Tx) error {
+			tx.With(WithName("x")).Get("/x", func(w http.ResponseWriter, req *http.Request) {})
+			tx.With(WithName("x")).Get("/y", func(w http.ResponseWriter, req *http.Request) {})
+			return nil
+		})
+	}()
+
+	if err := VerifyExtended(r, []*Want{
+		{"/keep", 200, "keep"},
+		{"/new", 404, "404 page not found\n"},
+		{"/x", 404, "404 page not found\n"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The lock is released after a panic
+	r.Get("/after", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("after"))
+	})
+	if err := Verify(r, []*Want{{"/after", 200, "after"}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test requests never observe a partially applied update
+func TestMuxUpdateAtomic(t *testing.T) {
+	r := NewRouter()
+	const routes = 20
+
+	register := func(tx *Tx, version string) {
+		for i := 0; i < routes; i++ {
+			tx.Get(fmt.Sprintf("/v/%d/:id", i), func(w http.ResponseWriter, req *http.Request) {
+				_, _ = w.Write([]byte(version))
+			})
+		}
+	}
+	_ = r.Update(func(tx *Tx) error {
+		register(tx, "A")
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				// Routes are removed and re-added in one update, so they never go missing
+				for i := 0; i < routes; i++ {
+					w := httptest.NewRecorder()
+					r.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/v/%d/x", i), nil))
+					if w.Code != http.StatusOK {
+						t.Errorf("Route %d: expected status 200, got %d", i, w.Code)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for n := 0; n < 50; n++ {
+		version := "A"
+		if n%2 == 0 {
+			version = "B"
+		}
+		_ = r.Update(func(tx *Tx) error {
+			for i := 0; i < routes; i++ {
+				tx.Remove("GET", fmt.Sprintf("/v/%d/:id", i))
+			}
+			register(tx, version)
+			return nil
+		})
+	}
+
+	close(stop)
+	wg.Wait()
+}
+
+// Test building the route table once for the staged routes
+func TestMuxCompile(t *testing.T) {
+	r := NewRouter()
+	for i := 0; i < 100; i++ {
+		r.Get(fmt.Sprintf("/items/%d/:id", i), func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte("item " + URLParam(req, "id")))
+		})
+	}
+	r.Remove("GET", "/items/99/:id")
+
+	// Routes are staged until the table is built
+	if n := len(r.doubleArray.data.Load().endpoints); n != 0 {
+		t.Fatalf("Expected no published routes before Compile, got %d", n)
+	}
+	r.Compile()
+	data := r.doubleArray.data.Load()
+	if n := len(data.endpoints); n != 99 {
+		t.Fatalf("Expected 99 routes after Compile, got %d", n)
+	}
+	r.Compile()
+	if r.doubleArray.data.Load() != data {
+		t.Error("Expected Compile without changes to keep the table")
+	}
+
+	// Routes registered after serving are built by the next request
+	r.Get("/late", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("late"))
+	})
+	err := r.Update(func(tx *Tx) error {
+		tx.Remove("GET", "/items/0/:id")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyExtended(r, []*Want{
+		{"/items/1/7", 200, "item 7"},
+		{"/items/0/7", 404, "404 page not found\n"},
+		{"/items/99/7", 404, "404 page not found\n"},
+		{"/late", 200, "late"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
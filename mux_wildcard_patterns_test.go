@@ -73,14 +73,9 @@ func TestMuxWildcardWithParameters(t *testing.T) {
 					}
 				}
 
-				// Extract wildcard portion manually
-				// This demonstrates how users might extract wildcard values
-				prefix := tt.pattern[:strings.Index(tt.pattern, "*")]
-				for name, value := range tt.wantParams {
-					prefix = strings.Replace(prefix, ":"+name, value, 1)
-				}
-				wildcard := strings.TrimPrefix(req.URL.Path, prefix)
-				
+				// Unnamed wildcard value is stored under WildcardKey
+				wildcard := URLParam(req, WildcardKey)
+
 				_, _ = w.Write([]byte(fmt.Sprintf("wildcard=%s", wildcard)))
 			})
 
@@ -414,4 +409,49 @@ func TestMuxComplexWildcardScenarios(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+// Test named wildcard captures
+func TestMuxNamedWildcard(t *testing.T) {
+	r := NewRouter()
+
+	r.Get("/files/*filepath", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("file=" + URLParam(req, "filepath")))
+	})
+	r.Get("/users/:id/*rest", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(URLParam(req, "id") + " rest=" + URLParam(req, "rest")))
+	})
+	r.Get("/raw/*", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("raw=" + URLParam(req, WildcardKey)))
+	})
+
+	if err := Verify(r, []*Want{
+		{"/files/docs/a.txt", 200, "file=docs/a.txt"},
+		{"/files/", 200, "file="},
+		{"/users/42/x/y", 200, "42 rest=x/y"},
+		{"/raw/a/b", 200, "raw=a/b"},
+		{"/files", 404, "404 page not found\n"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test invalid named wildcard patterns
+func TestMuxNamedWildcardInvalid(t *testing.T) {
+	patterns := []string{
+		"/files/*name/more",
+		"/files/*na:me",
+		"/files/*a/*b",
+	}
+
+	for _, pattern := range patterns {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for pattern %q", pattern)
+				}
+			}()
+			NewRouter().Get(pattern, func(w http.ResponseWriter, req *http.Request) {})
+		}()
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package bon
+
+const raceEnabled = false
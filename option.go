@@ -0,0 +1,26 @@
+package bon
+
+// RouteOption configures a route at registration time.
+// Options are applied to routes registered through Mux.With, Group.With, Route.With or Tx.With.
+type RouteOption func(*endpoint)
+
+// WithName names the route so that its URL can be generated with Mux.URL.
+// Route names must be unique within a Mux.
+func WithName(name string) RouteOption {
+	return func(ep *endpoint) {
+		ep.name = name
+	}
+}
+
+// WithMeta attaches value to the route under key. Middleware reads it with
+// RouteInfo, and Mux.Routes lists it.
+//
+//	r.With(bon.WithMeta("scope", "users:read")).Get("/users/:id", getUser)
+func WithMeta(key string, value any) RouteOption {
+	return func(ep *endpoint) {
+		if ep.meta == nil {
+			ep.meta = make(map[string]any)
+		}
+		ep.meta[key] = value
+	}
+}
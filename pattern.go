@@ -0,0 +1,172 @@
+package bon
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Pattern is a route pattern compiled for matching paths outside a Mux, for
+// example to rewrite them (see the rewrite package). It matches like a route
+// registered with the pattern.
+type Pattern struct {
+	pattern      string
+	matchPattern string
+	keys         []string
+	constraints  []func(string) bool
+}
+
+// ParsePattern compiles a route pattern. An invalid pattern returns a
+// *RouteError wrapping ErrInvalidRoute.
+func ParsePattern(pattern string) (*Pattern, error) {
+	if err := validatePattern(pattern); err != nil {
+		return nil, invalidRouteError("", pattern, err)
+	}
+	p := &Pattern{pattern: pattern, matchPattern: pattern}
+	if !isStaticPattern(pattern) {
+		// Already validated above
+		p.matchPattern, p.constraints, _ = compileConstraints(pattern)
+		p.keys = extractParamKeys(p.matchPattern)
+	}
+	return p, nil
+}
+
+// String returns the pattern as given to ParsePattern
+func (p *Pattern) String() string {
+	return p.pattern
+}
+
+// Keys returns the parameter names of the pattern in order. An unnamed
+// wildcard is named WildcardKey.
+func (p *Pattern) Keys() []string {
+	return p.keys
+}
+
+// Match reports whether path matches the pattern and returns the parameter
+// values in the order of Keys. A missing optional parameter is empty.
+func (p *Pattern) Match(path string) ([]string, bool) {
+	if p.keys == nil {
+		return nil, path == p.matchPattern
+	}
+	values := make([]string, len(p.keys))
+	matched, n := matchPatternOptimizedInPlace(p.matchPattern, path, values)
+	if !matched || !checkConstraints(p.constraints, values[:n]) {
+		return nil, false
+	}
+	return values, true
+}
+
+type (
+	// Template is a target, such as a URL, into which the parameter values
+	// of a pattern are substituted (see Pattern.Template)
+	Template struct {
+		target string
+		parts  []templatePart
+	}
+
+	// templatePart is a literal of the target, or a parameter when index >= 0
+	templatePart struct {
+		literal string
+		index   int  // Parameter index in the keys of the pattern (-1 for literals)
+		query   bool // Whether the parameter is in the query of the target
+	}
+)
+
+// Template compiles a target for the parameters of the pattern. In to, :name
+// is replaced by the parameter name and * by the wildcard of the pattern:
+//
+//	p, _ := bon.ParsePattern("/blog/:year/:slug")
+//	t, _ := p.Template("/articles/:slug?y=:year")
+//
+// Names starting with a digit are literal, as ports are. An error is returned
+// for an empty target and for a parameter that the pattern does not have.
+func (p *Pattern) Template(to string) (*Template, error) {
+	if to == "" {
+		return nil, fmt.Errorf("target cannot be empty")
+	}
+	wildcard := -1
+	if i := strings.LastIndexByte(p.matchPattern, '/'); strings.HasPrefix(p.matchPattern[i+1:], "*") {
+		wildcard = len(p.keys) - 1
+	}
+
+	var (
+		parts []templatePart
+		start = 0
+		query = false
+	)
+	literal := func(end int) {
+		if end > start {
+			parts = append(parts, templatePart{literal: to[start:end], index: -1})
+			query = query || strings.Contains(to[start:end], "?")
+		}
+	}
+	for i := 0; i < len(to); i++ {
+		switch to[i] {
+		case ':':
+			j := i + 1
+			for j < len(to) && isValidParamChar(to[j]) && strings.IndexByte("&=#", to[j]) < 0 {
+				j++
+			}
+			name := to[i+1 : j]
+			// Ports and schemes ("https://") are literal
+			if name == "" || (name[0] >= '0' && name[0] <= '9') {
+				continue
+			}
+			index := slices.Index(p.keys, name)
+			if index < 0 {
+				return nil, fmt.Errorf("target %q has unknown parameter %q", to, name)
+			}
+			literal(i)
+			parts = append(parts, templatePart{index: index, query: query})
+			start, i = j, j-1
+		case '*':
+			if wildcard < 0 {
+				return nil, fmt.Errorf("target %q has * but the pattern has no wildcard", to)
+			}
+			literal(i)
+			parts = append(parts, templatePart{index: wildcard, query: query})
+			start = i + 1
+		}
+	}
+	literal(len(to))
+	return &Template{target: to, parts: parts}, nil
+}
+
+// String returns the target as given to Pattern.Template
+func (t *Template) String() string {
+	return t.target
+}
+
+// Expand returns the target with values, in the order of the keys of the
+// pattern, escaped for the path or the query of the target. Wildcard values
+// keep their slashes, but a value substituted after a slash has its leading
+// slashes removed, so that a target starting with a path never becomes a
+// protocol-relative URL ("/old//evil.com" matching "/old/*" with the target
+// "/*" expands to "/evil.com").
+func (t *Template) Expand(values []string) string {
+	var b strings.Builder
+	for _, part := range t.parts {
+		switch {
+		case part.index < 0:
+			b.WriteString(part.literal)
+		case part.query:
+			b.WriteString(url.QueryEscape(values[part.index]))
+		default:
+			value := values[part.index]
+			if s := b.String(); s == "" || s[len(s)-1] == '/' {
+				value = strings.TrimLeft(value, "/")
+				if s == "" && value != values[part.index] {
+					b.WriteByte('/')
+				}
+			}
+			for i, segment := range strings.Split(value, "/") {
+				if i > 0 {
+					b.WriteByte('/')
+				}
+				b.WriteString(url.PathEscape(segment))
+			}
+		}
+	}
+	return b.String()
+}
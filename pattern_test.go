@@ -0,0 +1,87 @@
+package bon
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Test matching paths with a compiled pattern
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    []string
+		ok      bool
+	}{
+		{"/about", "/about", nil, true},
+		{"/about", "/about/", nil, false},
+		{"/users/:id", "/users/42", []string{"42"}, true},
+		{"/users/:id", "/users/42/posts", nil, false},
+		{"/users/:id<int>", "/users/abc", nil, false},
+		{"/files/{name:[a-z]+}.:ext", "/files/report.pdf", []string{"report", "pdf"}, true},
+		{"/docs/:lang?", "/docs", []string{""}, true},
+		{"/docs/:lang?", "/docs/en", []string{"en"}, true},
+		{"/static/*", "/static/css/site.css", []string{"css/site.css"}, true},
+		{"/src/:repo/*path", "/src/bon/mux.go", []string{"bon", "mux.go"}, true},
+	}
+
+	for _, tt := range tests {
+		p, err := ParsePattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("%q: unexpected error %v", tt.pattern, err)
+		}
+		got, ok := p.Match(tt.path)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q.Match(%q) = %q, %v, want %q, %v", tt.pattern, tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	p, _ := ParsePattern("/src/:repo/*")
+	if keys := p.Keys(); !reflect.DeepEqual(keys, []string{"repo", WildcardKey}) {
+		t.Errorf("Unexpected keys %q", keys)
+	}
+
+	if _, err := ParsePattern("/users/:id<nope>"); !errors.Is(err, ErrInvalidRoute) {
+		t.Errorf("Expected invalid route, got %v", err)
+	}
+}
+
+// Test substituting parameter values into a target
+func TestPatternTemplate(t *testing.T) {
+	tests := []struct {
+		pattern string
+		to      string
+		path    string
+		want    string
+	}{
+		{"/blog/:year/:slug", "/articles/:slug?y=:year", "/blog/2024/a b", "/articles/a%20b?y=2024"},
+		{"/docs/*", "https://docs.example.com:8443/*", "/docs/a/b c", "https://docs.example.com:8443/a/b%20c"},
+		{"/src/:repo/*path", "/repos/:repo/tree/:path?from=*", "/src/bon/a/b", "/repos/bon/tree/a/b?from=a%2Fb"},
+		{"/old/*", "/*", "/old//evil.com", "/evil.com"},
+		{"/old/*", "*", "/old//evil.com", "/evil.com"},
+		{"/old/*", "/new/*", "/old///a", "/new/a"},
+	}
+
+	for _, tt := range tests {
+		p, err := ParsePattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("%q: unexpected error %v", tt.pattern, err)
+		}
+		tmpl, err := p.Template(tt.to)
+		if err != nil {
+			t.Fatalf("%q: unexpected error %v", tt.to, err)
+		}
+		values, _ := p.Match(tt.path)
+		if got := tmpl.Expand(values); got != tt.want {
+			t.Errorf("%q with %q = %q, want %q", tt.to, tt.path, got, tt.want)
+		}
+	}
+
+	p, _ := ParsePattern("/users/:id")
+	for _, to := range []string{"", "/accounts/:name", "/files/*"} {
+		if _, err := p.Template(to); err == nil {
+			t.Errorf("%q: expected error", to)
+		}
+	}
+}
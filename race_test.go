@@ -0,0 +1,7 @@
+//go:build race
+
+package bon
+
+// sync.Pool drops items at random under the race detector, so pooled
+// values are allocated again and allocation counts are not stable
+const raceEnabled = true
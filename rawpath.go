@@ -0,0 +1,94 @@
+package bon
+
+import (
+	"net/url"
+	"strings"
+)
+
+// EncodedSlashPolicy sets how encoded slashes (%2F) in the request path are
+// handled when routes are matched with UseRawPath
+type EncodedSlashPolicy uint8
+
+const (
+	// EncodedSlashDecode decodes encoded slashes in parameter values, so that
+	// "/objects/a%2Fb" matches "/objects/:key" with key "a/b"
+	EncodedSlashDecode EncodedSlashPolicy = iota
+	// EncodedSlashKeep leaves encoded slashes in parameter values as "%2F"
+	EncodedSlashKeep
+	// EncodedSlashReject answers requests with encoded slashes with the 404
+	// handler
+	EncodedSlashReject
+)
+
+// rawRoutePath returns the path of u to match routes against with
+// UseRawPath: the escaped path, decoded except for encoded slashes and
+// percent signs ("%2F" and "%25"), which unescapeParams decodes from the
+// parameters
+func rawRoutePath(u *url.URL) string {
+	if u.RawPath == "" {
+		// The path has no encoded slashes
+		if strings.IndexByte(u.Path, '%') < 0 {
+			return u.Path
+		}
+		return strings.ReplaceAll(u.Path, "%", "%25")
+	}
+
+	// Escapes are valid (the raw path is dropped otherwise)
+	p := u.EscapedPath()
+	var b strings.Builder
+	b.Grow(len(p))
+	for i := 0; i < len(p); i++ {
+		if p[i] != '%' || i+2 >= len(p) {
+			b.WriteByte(p[i])
+			continue
+		}
+		switch c := unhex(p[i+1])<<4 | unhex(p[i+2]); c {
+		case '/':
+			b.WriteString("%2F")
+		case '%':
+			b.WriteString("%25")
+		default:
+			b.WriteByte(c)
+		}
+		i += 2
+	}
+	return b.String()
+}
+
+// unescapeParams decodes the parameters matched against a path from
+// rawRoutePath, leaving encoded slashes as "%2F" when keepSlashes is true
+func (ctx *Context) unescapeParams(keepSlashes bool) {
+	for i, v := range ctx.params.values {
+		if strings.IndexByte(v, '%') < 0 {
+			continue
+		}
+		var b strings.Builder
+		b.Grow(len(v))
+		for j := 0; j < len(v); j++ {
+			switch {
+			case strings.HasPrefix(v[j:], "%25"):
+				b.WriteByte('%')
+				j += 2
+			case strings.HasPrefix(v[j:], "%2F") && !keepSlashes:
+				b.WriteByte('/')
+				j += 2
+			default:
+				b.WriteByte(v[j])
+			}
+		}
+		ctx.params.values[i] = b.String()
+	}
+}
+
+// Value of hexadecimal digit c
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10
+	}
+	return 0
+}
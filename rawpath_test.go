@@ -0,0 +1,149 @@
+package bon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test matching routes against the escaped path
+func TestMuxUseRawPath(t *testing.T) {
+	newRouter := func(raw bool, policy EncodedSlashPolicy) *Mux {
+		r := NewRouter()
+		r.UseRawPath = raw
+		r.EncodedSlashes = policy
+		r.Get("/objects/:key", func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte("key=" + URLParam(req, "key")))
+		})
+		r.Get("/objects/:key/acl", func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte("acl=" + URLParam(req, "key")))
+		})
+		r.Get("/café/:name", func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte("name=" + URLParam(req, "name")))
+		})
+		r.Get("/files/*", func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte("file=" + URLParam(req, WildcardKey)))
+		})
+		r.Get("/a b", func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte("static"))
+		})
+		return r
+	}
+
+	tests := []struct {
+		name     string
+		raw      bool
+		policy   EncodedSlashPolicy
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		// Encoded slashes separate segments by default
+		{"default", false, EncodedSlashDecode, "/objects/a%2Fb", http.StatusNotFound, "404 page not found\n"},
+		{"default", false, EncodedSlashDecode, "/objects/a%2Fb/acl", http.StatusNotFound, "404 page not found\n"},
+
+		{"decode", true, EncodedSlashDecode, "/objects/a%2Fb", http.StatusOK, "key=a/b"},
+		{"decode", true, EncodedSlashDecode, "/objects/a%2fb%2Fc/acl", http.StatusOK, "acl=a/b/c"},
+		{"decode", true, EncodedSlashDecode, "/objects/a%252Fb", http.StatusOK, "key=a%2Fb"},
+		{"decode", true, EncodedSlashDecode, "/objects/100%25", http.StatusOK, "key=100%"},
+		{"decode", true, EncodedSlashDecode, "/objects/a%20b", http.StatusOK, "key=a b"},
+		{"decode", true, EncodedSlashDecode, "/caf%C3%A9/x%2Fy", http.StatusOK, "name=x/y"},
+		{"decode", true, EncodedSlashDecode, "/files/a%2Fb/c", http.StatusOK, "file=a/b/c"},
+		{"decode", true, EncodedSlashDecode, "/a%20b", http.StatusOK, "static"},
+		{"decode", true, EncodedSlashDecode, "/objects/a/b", http.StatusNotFound, "404 page not found\n"},
+
+		{"keep", true, EncodedSlashKeep, "/objects/a%2fb", http.StatusOK, "key=a%2Fb"},
+		{"keep", true, EncodedSlashKeep, "/objects/a%252Fb%20c", http.StatusOK, "key=a%2Fb c"},
+
+		{"reject", true, EncodedSlashReject, "/objects/a%2Fb", http.StatusNotFound, "404 page not found\n"},
+		{"reject", true, EncodedSlashReject, "/files/a%2Fb", http.StatusNotFound, "404 page not found\n"},
+		{"reject", true, EncodedSlashReject, "/objects/a%252Fb", http.StatusOK, "key=a%2Fb"},
+	}
+
+	for _, tt := range tests {
+		r := newRouter(tt.raw, tt.policy)
+		req := httptest.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode || w.Body.String() != tt.wantBody {
+			t.Errorf("%s %s: expected %d %q, got %d %q", tt.name, tt.path, tt.wantCode, tt.wantBody, w.Code, w.Body.String())
+		}
+	}
+}
+
+// Test that parameters are unescaped for ContextHandlerFunc and path values
+func TestMuxUseRawPathHandlers(t *testing.T) {
+	r := NewRouter()
+	r.UseRawPath = true
+	r.PathValues = true
+	r.Handle("GET", "/objects/:key", ContextHandlerFunc(func(w http.ResponseWriter, req *http.Request, ctx *Context) {
+		_, _ = w.Write([]byte(ctx.GetParam("key") + " " + req.PathValue("key")))
+	}))
+
+	req := httptest.NewRequest("GET", "/objects/a%2Fb%25", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if got := w.Body.String(); got != "a/b% a/b%" {
+		t.Errorf("Expected unescaped parameters, got %q", got)
+	}
+}
+
+// Test stripping a mount prefix matched against the escaped path
+func TestMuxUseRawPathMount(t *testing.T) {
+	sub := NewRouter()
+	sub.Get("/*", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(URLParam(req, "tenant") + " " + req.URL.Path + " " + req.URL.RawPath))
+	})
+	r := NewRouter()
+	r.UseRawPath = true
+	r.Mount("/t/:tenant", sub)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/t/a%2Fb/x", "a/b /x "},
+		{"/t/a%2Fb/x%2Fy", "a/b /x/y /x%2Fy"},
+		{"/t/a%20b/x", "a b /x "},
+		{"/t/a/x", "a /x "},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+		if got := w.Body.String(); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.path, tt.want, got)
+		}
+	}
+}
+
+// Test redirecting to an alternate form of the escaped path
+func TestMuxUseRawPathRedirect(t *testing.T) {
+	r := NewRouter()
+	r.UseRawPath = true
+	r.RedirectTrailingSlash = true
+	r.RedirectFixedPath = true
+	r.RedirectCaseInsensitive = true
+	r.Get("/objects/:key", func(w http.ResponseWriter, req *http.Request) {})
+	r.Get("/docs/:name/", func(w http.ResponseWriter, req *http.Request) {})
+
+	tests := []struct {
+		path     string
+		location string
+	}{
+		{"/objects/x%2Fy/", "/objects/x%2Fy"},
+		{"/objects/a%20b%2Fc/?q=1", "/objects/a%20b%2Fc?q=1"},
+		{"/docs/x%2Fy", "/docs/x%2Fy/"},
+		{"/objects//x%2Fy", "/objects/x%2Fy"},
+		{"/OBJECTS/x%2Fy", "/objects/x%2Fy"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+		if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != tt.location {
+			t.Errorf("%s: expected 301 %q, got %d %q", tt.path, tt.location, w.Code, w.Header().Get("Location"))
+		}
+	}
+}
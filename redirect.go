@@ -0,0 +1,343 @@
+package bon
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// redirectPath redirects to an alternate form of the request path when the
+// requested form has no route but the alternate form does. The alternate forms
+// are tried according to the RedirectTrailingSlash, RedirectFixedPath and
+// RedirectCaseInsensitive options. p is the path routed, escaped with
+// UseRawPath.
+func (m *Mux) redirectPath(w http.ResponseWriter, r *http.Request, data *trieData, p string) bool {
+	if r.Method == http.MethodConnect || p == "" || p == "*" {
+		return false
+	}
+
+	// Alternate slash form
+	if m.RedirectTrailingSlash {
+		if alt, ok := toggleTrailingSlash(p); ok && m.hasRoute(data, r, r.Method, alt) {
+			m.redirectTo(w, r, alt)
+			return true
+		}
+	}
+
+	// Cleaned path, and its alternate slash form
+	if m.RedirectFixedPath {
+		if clean := cleanPath(p); clean != p {
+			if m.hasRoute(data, r, r.Method, clean) {
+				m.redirectTo(w, r, clean)
+				return true
+			}
+			if m.RedirectTrailingSlash {
+				if alt, ok := toggleTrailingSlash(clean); ok && m.hasRoute(data, r, r.Method, alt) {
+					m.redirectTo(w, r, alt)
+					return true
+				}
+			}
+			p = clean
+		}
+	}
+
+	// Case-insensitive match, and its alternate slash form
+	if m.RedirectCaseInsensitive {
+		if fixed, ok := m.findFoldPath(data, r, r.Method, p); ok {
+			m.redirectTo(w, r, fixed)
+			return true
+		}
+		if m.RedirectTrailingSlash {
+			if alt, ok := toggleTrailingSlash(p); ok {
+				if fixed, ok := m.findFoldPath(data, r, r.Method, alt); ok {
+					m.redirectTo(w, r, fixed)
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// hasRoute reports whether a route answers method and path
+func (m *Mux) hasRoute(data *trieData, r *http.Request, method, p string) bool {
+	ep, ctx := m.lookupMethod(data, r, method, p)
+	if ctx != nil {
+		m.contextPool.Put(ctx.reset())
+	}
+	if ep == nil && method == http.MethodHead && m.ImplicitHead {
+		return m.hasRoute(data, r, http.MethodGet, p)
+	}
+	return ep != nil
+}
+
+// findFoldPath returns the path of the route matching p case-insensitively.
+// Static text is taken from the route pattern, parameter values from p. Only
+// the routes whose segments fit p are tried.
+func (m *Mux) findFoldPath(data *trieData, r *http.Request, method, p string) (string, bool) {
+	// Static routes
+	fixed := ""
+	for _, idx := range data.staticFold[method][strings.ToLower(p)] {
+		static := data.endpoints[idx].pattern
+		if (fixed == "" || static < fixed) && data.choose(idx, r) != nil {
+			fixed = static
+		}
+	}
+	if fixed != "" {
+		return fixed, true
+	}
+
+	// Dynamic routes (best score wins)
+	var (
+		bestScore int
+		routes    []dynRoute
+	)
+	if root := data.dynamic[method]; root != nil {
+		routes = root.foldRoutes(p, 0, nil)
+	}
+	for _, route := range routes {
+		ep := data.endpoints[route.idx]
+		candidate, ok := foldPattern(ep.matchPattern, p)
+		if !ok || !m.hasRoute(data, r, method, candidate) {
+			continue
+		}
+		score := ep.score
+		if fixed == "" || score > bestScore {
+			fixed = candidate
+			bestScore = score
+		}
+	}
+	if fixed != "" {
+		return fixed, true
+	}
+
+	if method == http.MethodHead && m.ImplicitHead {
+		return m.findFoldPath(data, r, http.MethodGet, p)
+	}
+	return "", false
+}
+
+// foldPattern matches pattern against p ignoring the case of static text and
+// returns p with the static text spelled as in pattern
+func foldPattern(pattern, p string) (string, bool) {
+	patternSegments := strings.Split(pattern[1:], "/")
+	pathSegments := strings.Split(p[1:], "/")
+
+	var b strings.Builder
+	b.Grow(len(p))
+	for i, seg := range patternSegments {
+		// Trailing wildcard matches the rest of the path
+		if strings.HasPrefix(seg, "*") && i == len(patternSegments)-1 {
+			if i >= len(pathSegments) {
+				return "", false
+			}
+			b.WriteByte('/')
+			b.WriteString(strings.Join(pathSegments[i:], "/"))
+			return b.String(), true
+		}
+
+		if i >= len(pathSegments) {
+			// Omitted optional parameter
+			if i == len(patternSegments)-1 && isOptionalTail(seg) {
+				return b.String(), true
+			}
+			return "", false
+		}
+		value := pathSegments[i]
+		b.WriteByte('/')
+
+		switch idx := strings.IndexByte(seg, ':'); {
+		case seg == "*":
+			b.WriteString(value)
+		case idx >= 0:
+			// Static text before the parameter
+			if len(value) < idx || !strings.EqualFold(seg[:idx], value[:idx]) {
+				return "", false
+			}
+			b.WriteString(seg[:idx])
+
+			// Static text ending the segment after its last parameter
+			j := idx + 1
+			for j < len(seg) && !isParamNameEnd(seg[j]) {
+				j++
+			}
+			suffix := strings.TrimPrefix(strings.TrimPrefix(seg[j:], "?"), string(paramEnd))
+			if suffix == "" || strings.ContainsAny(suffix, ":*") {
+				b.WriteString(value[idx:])
+				break
+			}
+			if len(value) < idx+len(suffix) || !strings.EqualFold(value[len(value)-len(suffix):], suffix) {
+				return "", false
+			}
+			b.WriteString(value[idx : len(value)-len(suffix)])
+			b.WriteString(suffix)
+		default:
+			if !strings.EqualFold(seg, value) {
+				return "", false
+			}
+			b.WriteString(seg)
+		}
+	}
+
+	if len(patternSegments) != len(pathSegments) {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// toggleTrailingSlash returns p with the trailing slash added or removed
+func toggleTrailingSlash(p string) (string, bool) {
+	if p == "/" {
+		return "", false
+	}
+	if p[len(p)-1] == '/' {
+		p = p[:len(p)-1]
+	} else {
+		p += "/"
+	}
+	// Never produce a protocol-relative URL
+	if strings.HasPrefix(p, "//") {
+		return "", false
+	}
+	return p, true
+}
+
+// cleanPath returns the canonical path for p, eliminating . and .. elements
+// and repeated slashes while keeping the trailing slash (as http.ServeMux)
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		p = "/" + p
+	}
+	np := path.Clean(p)
+	if p[len(p)-1] == '/' && np != "/" {
+		np += "/"
+	}
+	return np
+}
+
+// redirectTo redirects with 301 for GET and 308 for other methods so that
+// the method and body are preserved
+func (m *Mux) redirectTo(w http.ResponseWriter, r *http.Request, p string) {
+	code := http.StatusMovedPermanently
+	if r.Method != http.MethodGet {
+		code = http.StatusPermanentRedirect
+	}
+
+	u := url.URL{Path: p, RawQuery: r.URL.RawQuery}
+	if m.UseRawPath {
+		// Keep the encoded slashes of the routed path
+		segments := strings.Split(p, "/")
+		for i, segment := range segments {
+			if unescaped, err := url.PathUnescape(segment); err == nil {
+				segments[i] = url.PathEscape(unescaped)
+			}
+		}
+		u.RawPath = strings.Join(segments, "/")
+		u.Path, _ = url.PathUnescape(u.RawPath)
+	}
+	http.Redirect(w, r, u.String(), code)
+}
+
+type (
+	// redirectRoute redirects to a target built from the route parameters
+	redirectRoute struct {
+		keys          []string // Parameter names of the pattern
+		target        *Template
+		code          int
+		preserveQuery bool
+	}
+
+	// RedirectOption configures a redirect route (see Mux.Redirect)
+	RedirectOption func(*redirectRoute)
+)
+
+// PreserveQuery appends the query of the request to the target of a
+// redirect route, after the query of the target if it has one
+func PreserveQuery() RedirectOption {
+	return func(rr *redirectRoute) {
+		rr.preserveQuery = true
+	}
+}
+
+// Redirect registers a route redirecting the requests matching from to to
+// with code (301, 302, 303, 307 or 308). In to, :name is replaced by the
+// parameter name of from and * by its wildcard value:
+//
+//	r.Redirect("/blog/:year/:slug", "/articles/:slug?y=:year", http.StatusMovedPermanently)
+//	r.Redirect("/docs/*", "https://docs.example.com/*", http.StatusFound, bon.PreserveQuery())
+//
+// The route is registered for GET and HEAD, and also for POST, PUT, PATCH and
+// DELETE with 307 and 308, which preserve the method. It competes with the
+// other routes of these methods on priority and is listed in Allow.
+//
+// Parameter values are escaped for the path or the query of the target. A
+// target referencing a parameter that from does not have, or an invalid
+// code, panics as Handle does.
+func (m *Mux) Redirect(from, to string, code int, options ...RedirectOption) {
+	rr := newRedirectRoute(resolvePatternPrefix(from), to, code, options)
+	for _, method := range redirectMethods(code) {
+		m.Handle(method, from, rr)
+	}
+}
+
+// redirectMethods returns the methods a redirect route with code is
+// registered for
+func redirectMethods(code int) []string {
+	if code == http.StatusTemporaryRedirect || code == http.StatusPermanentRedirect {
+		return []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+	return []string{http.MethodGet, http.MethodHead}
+}
+
+// newRedirectRoute creates the redirect handler for the full pattern
+func newRedirectRoute(pattern, to string, code int, options []RedirectOption) *redirectRoute {
+	rr, err := compileRedirect(pattern, to, code)
+	if err != nil {
+		panic(err.Error())
+	}
+	for _, option := range options {
+		option(rr)
+	}
+	return rr
+}
+
+// compileRedirect compiles the target of a redirect route for the full pattern
+func compileRedirect(pattern, to string, code int) (*redirectRoute, error) {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return nil, invalidRouteError(http.MethodGet, pattern, fmt.Errorf("invalid redirect status code %d", code))
+	}
+	p, err := ParsePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	target, err := p.Template(to)
+	if err != nil {
+		return nil, invalidRouteError(http.MethodGet, pattern, fmt.Errorf("redirect %w", err))
+	}
+	return &redirectRoute{keys: p.Keys(), target: target, code: code}, nil
+}
+
+func (rr *redirectRoute) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	values := make([]string, len(rr.keys))
+	for i, key := range rr.keys {
+		values[i] = URLParam(r, key)
+	}
+	target := rr.target.Expand(values)
+
+	if rr.preserveQuery && r.URL.RawQuery != "" {
+		if strings.Contains(target, "?") {
+			target += "&" + r.URL.RawQuery
+		} else {
+			target += "?" + r.URL.RawQuery
+		}
+	}
+	http.Redirect(w, r, target, rr.code)
+}
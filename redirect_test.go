@@ -0,0 +1,204 @@
+package bon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newRedirectTestRouter() *Mux {
+	r := NewRouter()
+	ok := func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}
+	r.Get("/users", ok)
+	r.Get("/docs/", ok)
+	r.Get("/Articles/:slug", ok)
+	r.Post("/forms/submit", ok)
+	r.Get("/About", ok)
+	r.Get("/Reports/:name.PDF", ok)
+	r.Get("/Guide/:lang?", ok)
+	r.Get("/API/Users/:id/Posts", ok)
+	r.Get("/Static/*", ok)
+	return r
+}
+
+// Test redirect policies for paths without a route
+func TestMuxRedirectPolicies(t *testing.T) {
+	tests := []struct {
+		name         string
+		configure    func(r *Mux)
+		method       string
+		path         string
+		wantCode     int
+		wantLocation string
+	}{
+		{"disabled by default", func(r *Mux) {}, "GET", "/users/", http.StatusNotFound, ""},
+		{"remove trailing slash", func(r *Mux) { r.RedirectTrailingSlash = true }, "GET", "/users/", http.StatusMovedPermanently, "/users"},
+		{"add trailing slash", func(r *Mux) { r.RedirectTrailingSlash = true }, "GET", "/docs", http.StatusMovedPermanently, "/docs/"},
+		{"keep query string", func(r *Mux) { r.RedirectTrailingSlash = true }, "GET", "/users/?page=2", http.StatusMovedPermanently, "/users?page=2"},
+		{"308 for other methods", func(r *Mux) { r.RedirectTrailingSlash = true }, "POST", "/forms/submit/", http.StatusPermanentRedirect, "/forms/submit"},
+		{"no route in alternate form", func(r *Mux) { r.RedirectTrailingSlash = true }, "GET", "/missing/", http.StatusNotFound, ""},
+		{"clean path", func(r *Mux) { r.RedirectFixedPath = true }, "GET", "/a/../users", http.StatusMovedPermanently, "/users"},
+		{"clean repeated slashes", func(r *Mux) { r.RedirectFixedPath = true }, "GET", "/docs//", http.StatusMovedPermanently, "/docs/"},
+		{"clean path and slash", func(r *Mux) { r.RedirectFixedPath = true; r.RedirectTrailingSlash = true }, "GET", "/x/..//docs", http.StatusMovedPermanently, "/docs/"},
+		{"case-insensitive static", func(r *Mux) { r.RedirectCaseInsensitive = true }, "GET", "/about", http.StatusMovedPermanently, "/About"},
+		{"case-insensitive param", func(r *Mux) { r.RedirectCaseInsensitive = true }, "GET", "/articles/Hello-World", http.StatusMovedPermanently, "/Articles/Hello-World"},
+		{"case-insensitive suffix", func(r *Mux) { r.RedirectCaseInsensitive = true }, "GET", "/reports/Q1.pdf", http.StatusMovedPermanently, "/Reports/Q1.PDF"},
+		{"case-insensitive optional", func(r *Mux) { r.RedirectCaseInsensitive = true }, "GET", "/guide", http.StatusMovedPermanently, "/Guide"},
+		{"case-insensitive nested", func(r *Mux) { r.RedirectCaseInsensitive = true }, "GET", "/api/users/Bob/posts", http.StatusMovedPermanently, "/API/Users/Bob/Posts"},
+		{"case-insensitive wildcard", func(r *Mux) { r.RedirectCaseInsensitive = true }, "GET", "/static/CSS/a.css", http.StatusMovedPermanently, "/Static/CSS/a.css"},
+		{"case-insensitive miss", func(r *Mux) { r.RedirectCaseInsensitive = true }, "GET", "/api/users/Bob/comments", http.StatusNotFound, ""},
+		{"case-insensitive and slash", func(r *Mux) { r.RedirectCaseInsensitive = true; r.RedirectTrailingSlash = true }, "GET", "/USERS/", http.StatusMovedPermanently, "/users"},
+		{"method mismatch is not redirected", func(r *Mux) { r.RedirectTrailingSlash = true }, "PUT", "/users/", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRedirectTestRouter()
+			tt.configure(r)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.wantCode, w.Code)
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("%s %s: expected Location %q, got %q", tt.method, tt.path, tt.wantLocation, got)
+			}
+		})
+	}
+}
+
+// Test existing routes are never redirected
+func TestMuxRedirectExactMatchWins(t *testing.T) {
+	r := newRedirectTestRouter()
+	r.RedirectTrailingSlash = true
+	r.RedirectFixedPath = true
+	r.RedirectCaseInsensitive = true
+	r.Get("/users/", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("slash"))
+	})
+
+	if err := Verify(r, []*Want{
+		{"/users", 200, "ok"},
+		{"/users/", 200, "slash"},
+		{"/About", 200, "ok"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"", "/"},
+		{"/", "/"},
+		{"a/b", "/a/b"},
+		{"/a//b", "/a/b"},
+		{"/a/./b/", "/a/b/"},
+		{"/a/../../b", "/b"},
+		{"/a/b/..", "/a"},
+	}
+
+	for _, tt := range tests {
+		if got := cleanPath(tt.path); got != tt.want {
+			t.Errorf("cleanPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// Test redirect routes substituting parameters into the target
+func TestMuxRedirect(t *testing.T) {
+	r := NewRouter()
+	r.Redirect("/blog/:year<int>/:slug", "/articles/:slug?y=:year", http.StatusMovedPermanently)
+	r.Redirect("/docs/*", "https://docs.example.com/*", http.StatusFound, PreserveQuery())
+	r.Redirect("/files/*path", "/storage/:path", http.StatusPermanentRedirect)
+	r.Redirect("/old", "/new?from=old", http.StatusTemporaryRedirect, PreserveQuery())
+	r.Redirect("/home", "http://example.com:8080/", http.StatusSeeOther)
+	r.Get("/blog/archive", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("archive"))
+	})
+	// Less specific than the redirect route
+	r.Get("/blog/*", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("blog"))
+	})
+
+	api := r.Group("/api/:version")
+	api.Redirect("/users/:id", "/:version/accounts/:id", http.StatusMovedPermanently)
+	r.Route().Redirect("/me", "/profile", http.StatusFound)
+	r.Redirect("/go/*", "/*", http.StatusFound)
+
+	tests := []struct {
+		method   string
+		target   string
+		code     int
+		location string
+	}{
+		{"GET", "/blog/2024/hello-world", 301, "/articles/hello-world?y=2024"},
+		{"GET", "/blog/2024/a%20b&c", 301, "/articles/a%20b&c?y=2024"},
+		{"HEAD", "/blog/2024/x", 301, "/articles/x?y=2024"},
+		{"POST", "/blog/2024/x", 405, ""},
+		{"GET", "/blog/archive", 200, ""},
+		{"GET", "/docs/guide/intro?lang=en", 302, "https://docs.example.com/guide/intro?lang=en"},
+		{"PUT", "/files/a/b%20c.txt", 308, "/storage/a/b%20c.txt"},
+		{"GET", "/old?x=1", 307, "/new?from=old&x=1"},
+		{"GET", "/old", 307, "/new?from=old"},
+		{"GET", "/home", 303, "http://example.com:8080/"},
+		{"GET", "/api/v2/users/7", 301, "/v2/accounts/7"},
+		{"GET", "/me?x=1", 302, "/profile"},
+		// Never a protocol-relative URL
+		{"GET", "/go//evil.com", 302, "/evil.com"},
+		{"GET", "/go/%2Fevil.com", 302, "/evil.com"},
+		{"GET", "/go/%5Cevil.com", 302, "/%5Cevil.com"},
+		// Constraint failure is not redirected
+		{"GET", "/blog/latest/x", 200, ""},
+		{"DELETE", "/files/a", 308, "/storage/a"},
+		{"DELETE", "/old", 307, "/new?from=old"},
+		{"DELETE", "/home", 405, ""},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+		if w.Code != tt.code || w.Header().Get("Location") != tt.location {
+			t.Errorf("%s %s: expected %d %q, got %d %q", tt.method, tt.target, tt.code, tt.location, w.Code, w.Header().Get("Location"))
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/blog/2024/x", nil))
+	if allow := w.Header().Get("Allow"); allow != "GET, HEAD, OPTIONS" {
+		t.Errorf("Expected Allow GET, HEAD, OPTIONS, got %q", allow)
+	}
+}
+
+// Test invalid redirect routes
+func TestMuxRedirectInvalid(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		code int
+	}{
+		{"/blog/:slug", "/articles/:name", http.StatusMovedPermanently},
+		{"/blog/:slug", "/articles/*", http.StatusMovedPermanently},
+		{"/blog/:slug", "", http.StatusMovedPermanently},
+		{"/blog/:slug", "/articles/:slug", http.StatusOK},
+		{"/blog/:slug<nope>", "/articles/:slug", http.StatusMovedPermanently},
+	}
+
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Redirect(%q, %q, %d): expected panic", tt.from, tt.to, tt.code)
+				}
+			}()
+			NewRouter().Redirect(tt.from, tt.to, tt.code)
+		}()
+	}
+}
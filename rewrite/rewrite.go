@@ -0,0 +1,189 @@
+// Package rewrite rewrites and redirects requests by rules matching the
+// path with bon route patterns:
+//
+//	rules, err := rewrite.New(
+//		rewrite.Rule{From: "/products/:id.php", To: "/items/:id"},
+//		rewrite.Rule{From: "/blog/*path", To: "https://blog.example.com/:path", Code: http.StatusMovedPermanently},
+//	)
+//	r.Pre(rules.Middleware)
+//
+// Rules are tried in order and the first matching rule applies.
+package rewrite
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nissy/bon/v2"
+)
+
+type (
+	// Rule rewrites or redirects the requests whose path matches From
+	Rule struct {
+		// Route pattern matched against the path (e.g. "/products/:id<int>")
+		From string
+		// Target with :name replaced by the parameter name of From, and * by
+		// its wildcard, as for bon.Mux.Redirect (e.g. "/items/:id")
+		To string
+		// Redirect status code: 301, 302, 303, 307 or 308 (0 rewrites the request)
+		Code int
+	}
+
+	// Rules is an ordered list of compiled rules
+	Rules struct {
+		rules []rule
+	}
+
+	// Result is the outcome of a rule matching a path
+	Result struct {
+		Rule   Rule   // Matching rule
+		Target string // To of the rule with the parameters substituted
+	}
+
+	rule struct {
+		Rule
+		from *bon.Pattern
+		to   *bon.Template
+	}
+)
+
+// New compiles rules. An error is returned for an invalid pattern, target
+// or status code, and for a target referencing an unknown parameter.
+func New(rules ...Rule) (*Rules, error) {
+	rs := &Rules{rules: make([]rule, 0, len(rules))}
+	for i, r := range rules {
+		compiled, err := compile(r)
+		if err != nil {
+			return nil, fmt.Errorf("rewrite: rule %d (%s): %w", i+1, r.From, err)
+		}
+		rs.rules = append(rs.rules, compiled)
+	}
+	return rs, nil
+}
+
+// Load reads rules from r, one per line with the pattern, the target and an
+// optional redirect status code separated by spaces. Empty lines and lines
+// starting with # are skipped:
+//
+//	# Legacy pages
+//	/products/:id.php   /items/:id
+//	/blog/*path         https://blog.example.com/:path   301
+func Load(r io.Reader) (*Rules, error) {
+	rs := &Rules{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("rewrite: line %d: expected pattern, target and optional status code", line)
+		}
+		r := Rule{From: fields[0], To: fields[1]}
+		if len(fields) == 3 {
+			code, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("rewrite: line %d: invalid status code %q", line, fields[2])
+			}
+			r.Code = code
+		}
+		compiled, err := compile(r)
+		if err != nil {
+			return nil, fmt.Errorf("rewrite: line %d (%s): %w", line, r.From, err)
+		}
+		rs.rules = append(rs.rules, compiled)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("rewrite: %w", err)
+	}
+	return rs, nil
+}
+
+// LoadFile reads rules from the named file (see Load)
+func LoadFile(name string) (*Rules, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("rewrite: %w", err)
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Compile rule
+func compile(r Rule) (rule, error) {
+	switch r.Code {
+	case 0, http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return rule{}, fmt.Errorf("invalid redirect status code %d", r.Code)
+	}
+	if r.Code == 0 && (!strings.HasPrefix(r.To, "/") || strings.HasPrefix(r.To, "//")) {
+		return rule{}, fmt.Errorf("rewrite target %q must be a path", r.To)
+	}
+
+	from, err := bon.ParsePattern(r.From)
+	if err != nil {
+		return rule{}, err
+	}
+	to, err := from.Template(r.To)
+	if err != nil {
+		return rule{}, err
+	}
+	return rule{Rule: r, from: from, to: to}, nil
+}
+
+// Match returns the result of the first rule matching path, without
+// rewriting or redirecting anything. It is meant for testing rules.
+func (rs *Rules) Match(path string) (Result, bool) {
+	for i := range rs.rules {
+		r := &rs.rules[i]
+		if values, ok := r.from.Match(path); ok {
+			return Result{Rule: r.Rule, Target: r.to.Expand(values)}, true
+		}
+	}
+	return Result{}, false
+}
+
+// Middleware applies the first rule matching the request path. A redirect
+// is answered directly, and a rewritten request is passed to next, so the
+// rules are used with Mux.Pre to route the rewritten path:
+//
+//	r.Pre(rules.Middleware)
+//
+// The query of the request is kept unless the target has a query.
+func (rs *Rules) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, ok := rs.Match(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		target, err := url.Parse(result.Target)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if target.RawQuery == "" && !target.ForceQuery {
+			target.RawQuery = r.URL.RawQuery
+		}
+
+		if result.Rule.Code != 0 {
+			http.Redirect(w, r, target.String(), result.Rule.Code)
+			return
+		}
+
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.URL = new(url.URL)
+		*r2.URL = *r.URL
+		r2.URL.Path = target.Path
+		r2.URL.RawPath = target.RawPath
+		r2.URL.RawQuery = target.RawQuery
+		next.ServeHTTP(w, r2)
+	})
+}
@@ -0,0 +1,188 @@
+package rewrite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nissy/bon/v2"
+)
+
+func TestMatch(t *testing.T) {
+	rules, err := New(
+		Rule{From: "/products/:id<int>.php", To: "/items/:id"},
+		Rule{From: "/products/:name.php", To: "/search?q=:name"},
+		Rule{From: "/blog/*path", To: "https://blog.example.com/:path", Code: http.StatusMovedPermanently},
+		Rule{From: "/docs/*", To: "/manual/*", Code: http.StatusFound},
+		Rule{From: "/about", To: "/company/about"},
+		Rule{From: "/checkout", To: "/orders", Code: http.StatusSeeOther},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path   string
+		target string
+		code   int
+		ok     bool
+	}{
+		{"/products/42.php", "/items/42", 0, true},
+		{"/products/red shoes.php", "/search?q=red+shoes", 0, true},
+		{"/blog/2024/hello world", "https://blog.example.com/2024/hello%20world", 301, true},
+		{"/docs/a/b", "/manual/a/b", 302, true},
+		{"/about", "/company/about", 0, true},
+		{"/about/", "", 0, false},
+		{"/checkout", "/orders", 303, true},
+		{"/items/42", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		result, ok := rules.Match(tt.path)
+		if ok != tt.ok || result.Target != tt.target || result.Rule.Code != tt.code {
+			t.Errorf("Match(%q) = %+v, %v, want %q %d %v", tt.path, result, ok, tt.target, tt.code, tt.ok)
+		}
+	}
+}
+
+func TestMatchOpenRedirect(t *testing.T) {
+	rules, err := New(
+		Rule{From: "/old/*path", To: "/:path", Code: http.StatusMovedPermanently},
+		Rule{From: "/legacy/*path", To: "/:path"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		path   string
+		target string
+	}{
+		{"/old//evil.com", "/evil.com"},
+		{"/old///evil.com/a", "/evil.com/a"},
+		{`/old/\\evil.com`, "/%5C%5Cevil.com"},
+		{"/legacy//evil.com", "/evil.com"},
+	} {
+		if result, ok := rules.Match(tt.path); !ok || result.Target != tt.target {
+			t.Errorf("Match(%q) = %+v, %v, want %q", tt.path, result, ok, tt.target)
+		}
+	}
+
+	r := bon.NewRouter()
+	r.Pre(rules.Middleware)
+	r.Get("/*", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(req.URL.Path))
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/old/%2Fevil.com", nil))
+	if location := w.Header().Get("Location"); location != "/evil.com" {
+		t.Errorf("Expected /evil.com, got %q", location)
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/legacy/%2Fevil.com", nil))
+	if body := w.Body.String(); body != "/evil.com" {
+		t.Errorf("Expected /evil.com, got %q", body)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	for _, r := range []Rule{
+		{From: "products", To: "/items"},
+		{From: "/products/:id<nope>", To: "/items/:id"},
+		{From: "/products/:id", To: "/items/:name"},
+		{From: "/products/:id", To: "/items/*"},
+		{From: "/products/:id", To: ""},
+		{From: "/products/:id", To: "https://example.com/:id"},
+		{From: "/products/:id", To: "//example.com/:id"},
+		{From: "/products/:id", To: "/items/:id", Code: 304},
+	} {
+		if _, err := New(r); err == nil {
+			t.Errorf("%+v: expected error", r)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	rules, err := Load(strings.NewReader(`
+# Legacy pages
+/products/:id.php   /items/:id
+
+/blog/*path         https://blog.example.com/:path   308
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result, ok := rules.Match("/blog/hello"); !ok || result.Target != "https://blog.example.com/hello" || result.Rule.Code != 308 {
+		t.Errorf("Unexpected result %+v, %v", result, ok)
+	}
+
+	for _, input := range []string{
+		"/products/:id.php",
+		"/products/:id.php /items/:id 301 extra",
+		"/products/:id.php /items/:id moved",
+		"/products/:id.php /items/:name",
+	} {
+		if _, err := Load(strings.NewReader(input)); err == nil || !strings.HasPrefix(err.Error(), "rewrite: line 1") {
+			t.Errorf("%q: expected error for line 1, got %v", input, err)
+		}
+	}
+
+	name := filepath.Join(t.TempDir(), "rules.txt")
+	if err := os.WriteFile(name, []byte("/old /new\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err = LoadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result, ok := rules.Match("/old"); !ok || result.Target != "/new" {
+		t.Errorf("Unexpected result %+v, %v", result, ok)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	rules, err := New(
+		Rule{From: "/products/:id.php", To: "/items/:id"},
+		Rule{From: "/search.php", To: "/search?source=legacy"},
+		Rule{From: "/old/*path", To: "/new/:path", Code: http.StatusMovedPermanently},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := bon.NewRouter()
+	r.Pre(rules.Middleware)
+	r.Get("/items/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("item " + bon.URLParam(req, "id") + " " + req.URL.RawQuery))
+	})
+	r.Get("/search", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("search " + req.URL.RawQuery))
+	})
+
+	tests := []struct {
+		target   string
+		code     int
+		body     string
+		location string
+	}{
+		{"/products/42.php?ref=home", 200, "item 42 ref=home", ""},
+		{"/search.php?q=shoes", 200, "search source=legacy", ""},
+		{"/old/a/b?x=1", 301, "", "/new/a/b?x=1"},
+		{"/items/7", 200, "item 7 ", ""},
+		{"/missing", 404, "404 page not found\n", ""},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+		if w.Code != tt.code || w.Header().Get("Location") != tt.location {
+			t.Errorf("%s: expected %d %q, got %d %q", tt.target, tt.code, tt.location, w.Code, w.Header().Get("Location"))
+		}
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%s: expected body %q, got %q", tt.target, tt.body, w.Body.String())
+		}
+	}
+}
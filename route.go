@@ -6,6 +6,7 @@ type Route struct {
 	mux         *Mux
 	middlewares []Middleware
 	prefix      string
+	options     []RouteOption
 }
 
 func (r *Route) Group(pattern string, middlewares ...Middleware) *Group {
@@ -13,6 +14,7 @@ func (r *Route) Group(pattern string, middlewares ...Middleware) *Group {
 		mux:         r.mux,
 		middlewares: append(r.middlewares, middlewares...),
 		prefix:      r.prefix + resolvePatternPrefix(pattern),
+		options:     r.options,
 	}
 }
 
@@ -20,6 +22,17 @@ func (r *Route) Route(middlewares ...Middleware) *Route {
 	return &Route{
 		mux:         r.mux,
 		middlewares: middlewares,
+		options:     r.options,
+	}
+}
+
+// With returns a copy of the route applying options to the routes registered through it
+func (r *Route) With(options ...RouteOption) *Route {
+	return &Route{
+		mux:         r.mux,
+		middlewares: append([]Middleware{}, r.middlewares...),
+		prefix:      r.prefix,
+		options:     append(append([]RouteOption{}, r.options...), options...),
 	}
 }
 
@@ -64,10 +77,29 @@ func (r *Route) Trace(pattern string, handlerFunc http.HandlerFunc, middlewares
 }
 
 func (r *Route) Handle(method, pattern string, handler http.Handler, middlewares ...Middleware) {
-	r.mux.Handle(method, r.prefix+resolvePatternPrefix(pattern), handler, append(r.middlewares, middlewares...)...)
+	r.mux.handle(method, r.prefix+resolvePatternPrefix(pattern), handler, append(r.middlewares, middlewares...), r.options)
+}
+
+// TryHandle registers a route like Handle, but returns an error instead of
+// panicking or replacing the route (see Mux.TryHandle)
+func (r *Route) TryHandle(method, pattern string, handler http.Handler, middlewares ...Middleware) error {
+	return r.mux.tryHandle(method, r.prefix+resolvePatternPrefix(pattern), handler, append(r.middlewares, middlewares...), r.options, true)
 }
 
 func (r *Route) FileServer(pattern, root string, middlewares ...Middleware) {
-	p := r.prefix + resolvePatternPrefix(pattern)
-	contentsHandle(r, p, r.mux.newFileServer(p, root).contents, middlewares...)
+	// The route prefix is added by Handle
+	contentsHandle(r, pattern, r.mux.newFileServer(root).contents, middlewares...)
+}
+
+// Mount dispatches requests for any method under prefix to handler (see Mux.Mount)
+func (r *Route) Mount(prefix string, handler http.Handler, middlewares ...Middleware) {
+	mountHandle(r, prefix, r.mux.newMount(r.prefix+resolvePatternPrefix(prefix), handler), middlewares...)
+}
+
+// Redirect registers a redirect route (see Mux.Redirect)
+func (r *Route) Redirect(from, to string, code int, options ...RedirectOption) {
+	rr := newRedirectRoute(r.prefix+resolvePatternPrefix(from), to, code, options)
+	for _, method := range redirectMethods(code) {
+		r.Handle(method, from, rr)
+	}
 }
@@ -0,0 +1,168 @@
+package bon
+
+import (
+	"iter"
+	"maps"
+	"net/http"
+	"strings"
+)
+
+// RouteKind is the kind of a route pattern
+type RouteKind uint8
+
+const (
+	RouteStatic   RouteKind = RouteKind(nodeKindStatic) // Static match (exact match)
+	RouteParam    RouteKind = RouteKind(nodeKindParam)  // Parameter match (:param)
+	RouteWildcard RouteKind = RouteKind(nodeKindAny)    // Wildcard match (*)
+)
+
+func (k RouteKind) String() string {
+	switch k {
+	case RouteStatic:
+		return "static"
+	case RouteParam:
+		return "param"
+	case RouteWildcard:
+		return "wildcard"
+	}
+	return "unknown"
+}
+
+// RouteEntry describes a registered route
+type RouteEntry struct {
+	Method      string         // HTTP method (e.g., "GET", or "*" for mounted handlers)
+	Host        string         // Host pattern (empty for routes registered on the Mux)
+	Pattern     string         // Full pattern including group prefixes (e.g., "/api/users/:id<int>")
+	Name        string         // Route name (empty when unnamed)
+	Kind        RouteKind      // Pattern kind
+	Params      []string       // Parameter names in pattern order (wildcard key last)
+	Middlewares int            // Number of route and group middlewares (global middlewares excluded)
+	Meta        map[string]any // Route metadata (see WithMeta)
+}
+
+// MatchedRoute describes the route that matched a request
+type MatchedRoute struct {
+	Method  string         // HTTP method (e.g., "GET", or "*" for mounted handlers)
+	Host    string         // Host pattern (empty for routes registered on the Mux)
+	Pattern string         // Full pattern, including the prefix of the Mux mounted from
+	Name    string         // Route name (empty when unnamed)
+	Meta    map[string]any // Route metadata (see WithMeta, must not be modified)
+}
+
+// Routes returns the registered routes in registration order
+func (m *Mux) Routes() iter.Seq[RouteEntry] {
+	return func(yield func(RouteEntry) bool) {
+		for _, ep := range m.snapshotEndpoints() {
+			if !yield(ep.entry()) {
+				return
+			}
+		}
+	}
+}
+
+// Walk calls fn for each registered route in registration order.
+// Walking stops at the first error, which is returned.
+func (m *Mux) Walk(fn func(route RouteEntry) error) error {
+	for route := range m.Routes() {
+		if err := fn(route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Registered endpoints (snapshots are never modified once published)
+func (m *Mux) snapshotEndpoints() []*endpoint {
+	return m.doubleArray.load().endpoints
+}
+
+// Describe endpoint
+func (ep *endpoint) entry() RouteEntry {
+	return RouteEntry{
+		Method:      ep.method,
+		Host:        ep.host,
+		Pattern:     ep.pattern,
+		Name:        ep.name,
+		Kind:        RouteKind(ep.kind),
+		Params:      append([]string(nil), ep.paramKeys...),
+		Middlewares: len(ep.middlewares),
+		Meta:        maps.Clone(ep.meta),
+	}
+}
+
+// RoutePattern returns the pattern of the route that matched r (e.g.,
+// "/users/:id"), or "" when no route matched. Unlike the request path, it
+// makes a low-cardinality label for metrics and logs.
+//
+// The Mux sets http.Request.Pattern to the method, host and pattern of the
+// matched route before running the middlewares, as http.ServeMux does.
+func RoutePattern(r *http.Request) string {
+	_, _, pattern := splitRequestPattern(r.Pattern)
+	return pattern
+}
+
+// RouteInfo describes the route that matched r, and reports whether a route
+// matched. It is available to all middlewares, including global ones.
+//
+//	func Metrics(next http.Handler) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//			next.ServeHTTP(w, r)
+//			route, _ := bon.RouteInfo(r)
+//			requests.WithLabelValues(route.Method, route.Pattern).Inc()
+//		})
+//	}
+//
+// The name and metadata of a route without parameters are stored in the
+// request context only when it has any, so that other routes without
+// parameters are served without allocating.
+func RouteInfo(r *http.Request) (MatchedRoute, bool) {
+	if r.Pattern == "" {
+		return MatchedRoute{}, false
+	}
+	var route MatchedRoute
+	route.Method, route.Host, route.Pattern = splitRequestPattern(r.Pattern)
+	// The context of an enclosing route may be left when none was stored
+	if ctx, ok := requestContext(r); ok && ctx.route != nil && ctx.pattern == r.Pattern {
+		route.Name = ctx.route.name
+		route.Meta = ctx.route.meta
+	}
+	return route, true
+}
+
+// Split request pattern ("[METHOD ][HOST]/path") into its parts. The method
+// of a pattern without one is "*".
+func splitRequestPattern(s string) (method, host, pattern string) {
+	method = methodAny
+	if i := strings.IndexAny(s, " \t"); i >= 0 && i < strings.IndexByte(s, '/') {
+		method, s = s[:i], strings.TrimLeft(s[i+1:], " \t")
+	}
+	if i := strings.IndexByte(s, '/'); i > 0 {
+		host, s = s[:i], s[i:]
+	}
+	return method, host, s
+}
+
+// needsContext reports whether ep has a name or metadata for RouteInfo,
+// which are stored in the request context
+func (ep *endpoint) needsContext() bool {
+	return ep.name != "" || ep.meta != nil
+}
+
+// requestPattern returns the request pattern of e for r, with the prefix of
+// the Mux mounted from
+func (m *Mux) requestPattern(r *http.Request, e *endpoint) string {
+	if m.parent == nil || r.Pattern == "" {
+		return e.requestPattern
+	}
+
+	// r.Pattern is the pattern of the mount route ("/prefix" or "/prefix/*")
+	_, host, prefix := splitRequestPattern(r.Pattern)
+	prefix = strings.TrimSuffix(strings.TrimSuffix(prefix, "*"), "/")
+	if e.host != "" {
+		host = e.host
+	}
+	if e.method == methodAny {
+		return host + prefix + e.pattern
+	}
+	return e.method + " " + host + prefix + e.pattern
+}
@@ -0,0 +1,148 @@
+package bon
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// Test route table introspection
+func TestMuxRoutes(t *testing.T) {
+	r := NewRouter()
+	h := func(w http.ResponseWriter, req *http.Request) {}
+
+	r.Use(WriteMiddleware("global"))
+	r.Get("/", h)
+	r.With(WithName("user"), WithMeta("scope", "users:read")).Get("/users/:id<int>", h, WriteMiddleware("A"))
+
+	api := r.Group("/api", WriteMiddleware("B"))
+	api.Post("/files/:bucket/*", h, WriteMiddleware("C"))
+
+	// Replacement keeps the registration order
+	r.Get("/", h, WriteMiddleware("D"))
+
+	want := []RouteEntry{
+		{Method: "GET", Pattern: "/", Kind: RouteStatic, Middlewares: 1},
+		{Method: "GET", Pattern: "/users/:id<int>", Name: "user", Kind: RouteParam, Params: []string{"id"}, Middlewares: 1, Meta: map[string]any{"scope": "users:read"}},
+		{Method: "POST", Pattern: "/api/files/:bucket/*", Kind: RouteWildcard, Params: []string{"bucket", "*"}, Middlewares: 2},
+	}
+
+	var got []RouteEntry
+	for route := range r.Routes() {
+		got = append(got, route)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Routes() = %+v, want %+v", got, want)
+	}
+}
+
+// Test walking the route table
+func TestMuxWalk(t *testing.T) {
+	r := NewRouter()
+	h := func(w http.ResponseWriter, req *http.Request) {}
+	r.Get("/a", h)
+	r.Get("/b", h)
+	r.Get("/c", h)
+
+	var patterns []string
+	errStop := errors.New("stop")
+	err := r.Walk(func(route RouteEntry) error {
+		patterns = append(patterns, route.Pattern)
+		if route.Pattern == "/b" {
+			return errStop
+		}
+		return nil
+	})
+
+	if err != errStop {
+		t.Errorf("Expected walk error to be returned, got %v", err)
+	}
+	if !reflect.DeepEqual(patterns, []string{"/a", "/b"}) {
+		t.Errorf("Expected walk to stop at /b, got %v", patterns)
+	}
+}
+
+func TestRouteKindString(t *testing.T) {
+	for kind, want := range map[RouteKind]string{
+		RouteStatic:   "static",
+		RouteParam:    "param",
+		RouteWildcard: "wildcard",
+		RouteKind(9):  "unknown",
+	} {
+		if got := kind.String(); got != want {
+			t.Errorf("RouteKind(%d).String() = %q, want %q", kind, got, want)
+		}
+	}
+}
+
+// Test the matched route seen by middleware
+func TestRouteInfo(t *testing.T) {
+	r := NewRouter()
+	var got MatchedRoute
+	var matched bool
+	var pattern string
+	record := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			got, matched = RouteInfo(req)
+			pattern = RoutePattern(req)
+			next.ServeHTTP(w, req)
+		})
+	}
+	r.Use(record)
+	h := func(w http.ResponseWriter, req *http.Request) {}
+
+	r.Get("/health", h)
+	r.With(WithName("about"), WithMeta("public", true)).Get("/about", h)
+	r.With(WithMeta("scope", "users:read"), WithMeta("audit", 2)).Get("/users/:id<int>", h)
+	r.With(MatchHeader("Accept", "text/csv"), WithName("csv")).Get("/users/:id<int>", h)
+	r.Host("api.example.com").Post("/items", h)
+
+	// Middleware of a mounted router sees the full pattern
+	sub := NewRouter()
+	sub.Use(record)
+	sub.Get("/", h)
+	sub.With(WithName("item")).Get("/items/:item", h)
+	r.Mount("/tenants/:tenant", sub)
+	r.Mount("/static", http.HandlerFunc(h))
+
+	tests := []struct {
+		method string
+		target string
+		header http.Header
+		want   MatchedRoute
+	}{
+		{"GET", "/health", nil, MatchedRoute{Method: "GET", Pattern: "/health"}},
+		{"HEAD", "/health", nil, MatchedRoute{Method: "GET", Pattern: "/health"}},
+		{"GET", "/about", nil, MatchedRoute{Method: "GET", Pattern: "/about", Name: "about", Meta: map[string]any{"public": true}}},
+		{"GET", "/users/1", nil, MatchedRoute{Method: "GET", Pattern: "/users/:id<int>", Meta: map[string]any{"scope": "users:read", "audit": 2}}},
+		{"GET", "/users/1", http.Header{"Accept": {"text/csv"}}, MatchedRoute{Method: "GET", Pattern: "/users/:id<int>", Name: "csv"}},
+		{"POST", "http://api.example.com/items", nil, MatchedRoute{Method: "POST", Host: "api.example.com", Pattern: "/items"}},
+		{"GET", "/tenants/acme/", nil, MatchedRoute{Method: "GET", Pattern: "/tenants/:tenant/"}},
+		{"GET", "/tenants/acme/items/1", nil, MatchedRoute{Method: "GET", Pattern: "/tenants/:tenant/items/:item", Name: "item"}},
+		{"DELETE", "/static/a.css", nil, MatchedRoute{Method: "*", Pattern: "/static/*"}},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, nil)
+		for key, values := range tt.header {
+			req.Header[key] = values
+		}
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		if !matched || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %s: RouteInfo = %+v, %v, want %+v", tt.method, tt.target, got, matched, tt.want)
+		}
+		if pattern != tt.want.Pattern {
+			t.Errorf("%s %s: RoutePattern = %q, want %q", tt.method, tt.target, pattern, tt.want.Pattern)
+		}
+	}
+
+	// Nothing matched
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+	if matched || pattern != "" {
+		t.Errorf("Expected no route for a 404, got %+v, %q", got, pattern)
+	}
+}
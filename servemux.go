@@ -0,0 +1,173 @@
+package bon
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// HandleFunc registers handlerFunc for an http.ServeMux pattern, taking the
+// method and host of the route from the pattern:
+//
+//	r.HandleFunc("GET /users/{id}", getUser) // bon.URLParam(r, "id")
+//	r.HandleFunc("POST api.example.com/files/{path...}", upload)
+//	r.HandleFunc("/{$}", home) // Only "/", for any method
+//
+// The pattern is converted to a route as follows:
+//   - A pattern without a method matches any method, like a mounted handler,
+//     and routes registered for the method win when as specific
+//   - {name} is the parameter :name and {name...} the wildcard *name
+//   - A trailing slash matches every path below it ("/static/" is
+//     "/static/*") unless it is followed by {$}
+//   - A host matches like Host(host)
+//
+// Patterns that http.ServeMux rejects, and escaped slashes and ':' or '*' in
+// literal segments, which bon cannot express, panic as Handle does. Routes
+// take priority over each other by bon's rules (see calculateScore), so
+// patterns that http.ServeMux reports as conflicting are accepted.
+func (m *Mux) HandleFunc(pattern string, handlerFunc http.HandlerFunc, middlewares ...Middleware) {
+	if err := m.tryHandleFunc(pattern, handlerFunc, middlewares, m.StrictRoutes); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryHandleFunc registers a route like HandleFunc, but returns a *RouteError
+// instead of panicking or registering the route (see TryHandle).
+func (m *Mux) TryHandleFunc(pattern string, handlerFunc http.HandlerFunc, middlewares ...Middleware) error {
+	return m.tryHandleFunc(pattern, handlerFunc, middlewares, true)
+}
+
+// tryHandleFunc registers a route for an http.ServeMux pattern. Duplicate
+// and shadowed routes are rejected when strict is true.
+func (m *Mux) tryHandleFunc(pattern string, handlerFunc http.HandlerFunc, middlewares []Middleware, strict bool) error {
+	method, host, p, err := parseServeMuxPattern(pattern)
+	if err != nil {
+		return invalidRouteError("", pattern, err)
+	}
+	var options []RouteOption
+	if host != "" {
+		options = append(options, withHost(host))
+	}
+	return m.tryHandle(method, p, handlerFunc, middlewares, options, strict)
+}
+
+// parseServeMuxPattern converts an http.ServeMux pattern
+// ("[METHOD ][HOST]/[PATH]") to the method, normalized host and pattern of
+// the equivalent route
+func parseServeMuxPattern(s string) (method, host, pattern string, err error) {
+	if s == "" {
+		return "", "", "", fmt.Errorf("empty pattern")
+	}
+
+	// Method
+	method, rest := methodAny, s
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		method, rest = s[:i], strings.TrimLeft(s[i+1:], " \t")
+		if !isToken(method) {
+			return "", "", "", fmt.Errorf("invalid method %q", method)
+		}
+	}
+
+	// Host
+	i := strings.IndexByte(rest, '/')
+	if i < 0 {
+		return "", "", "", fmt.Errorf("host/path missing /")
+	}
+	host, rest = rest[:i], rest[i:]
+	if host != "" {
+		if strings.ContainsAny(host, "{}") {
+			return "", "", "", fmt.Errorf("host %q contains '{' (missing initial '/'?)", host)
+		}
+		table, err := parseHostPattern(host)
+		if err != nil {
+			return "", "", "", err
+		}
+		host = table.pattern
+	}
+	if rest != cleanPath(rest) {
+		return "", "", "", fmt.Errorf("unclean path %q can never match", rest)
+	}
+
+	// Path segments
+	var b strings.Builder
+	seen := make(map[string]bool)
+	for rest != "" {
+		seg, next, more := strings.Cut(rest[1:], "/")
+		b.WriteByte('/')
+		switch {
+		case seg == "" && !more:
+			// Trailing slash matches the paths below it
+			b.WriteByte('*')
+		case seg == "{$}":
+			if more {
+				return "", "", "", fmt.Errorf("{$} not at end")
+			}
+		case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+			name, multi := strings.CutSuffix(seg[1:len(seg)-1], "...")
+			if multi && more {
+				return "", "", "", fmt.Errorf("{...} wildcard not at end")
+			}
+			if !isWildcardName(name) {
+				return "", "", "", fmt.Errorf("bad wildcard name %q", name)
+			}
+			if seen[name] {
+				return "", "", "", fmt.Errorf("duplicate wildcard name %q", name)
+			}
+			seen[name] = true
+			if multi {
+				b.WriteString("*" + name)
+			} else {
+				b.WriteString(":" + name)
+			}
+		case strings.ContainsAny(seg, "{}"):
+			return "", "", "", fmt.Errorf("bad wildcard segment %q (must be entire segment)", seg)
+		default:
+			literal, err := url.PathUnescape(seg)
+			if err != nil {
+				return "", "", "", fmt.Errorf("invalid escape in segment %q", seg)
+			}
+			if strings.Contains(literal, "/") {
+				return "", "", "", fmt.Errorf("escaped slash in segment %q is not supported", seg)
+			}
+			if strings.ContainsAny(literal, ":*") {
+				return "", "", "", fmt.Errorf("':' and '*' in segment %q are not supported", seg)
+			}
+			b.WriteString(literal)
+		}
+		if !more {
+			break
+		}
+		rest = "/" + next
+	}
+
+	return method, host, b.String(), nil
+}
+
+// Check if name is a valid wildcard name (a Go identifier)
+func isWildcardName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		if !unicode.IsLetter(c) && c != '_' && (i == 0 || !unicode.IsDigit(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check if s is a valid HTTP token (RFC 9110)
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 0x7f || c <= ' ' || strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
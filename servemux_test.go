@@ -0,0 +1,154 @@
+package bon
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test converting http.ServeMux patterns to routes
+func TestParseServeMuxPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		method  string
+		host    string
+		want    string
+	}{
+		{"/", "*", "", "/*"},
+		{"/{$}", "*", "", "/"},
+		{"GET /users/{id}", "GET", "", "/users/:id"},
+		{"GET\t /users/{id}/posts/{post}", "GET", "", "/users/:id/posts/:post"},
+		{"POST /files/{path...}", "POST", "", "/files/*path"},
+		{"/static/", "*", "", "/static/*"},
+		{"/static/{$}", "*", "", "/static/"},
+		{"DELETE Example.COM/items/{id}", "DELETE", "example.com", "/items/:id"},
+		{"example.com/", "*", "example.com", "/*"},
+		{"/a%20b/{x}", "*", "", "/a b/:x"},
+		{"GET /é/{nom}", "GET", "", "/é/:nom"},
+	}
+
+	for _, tt := range tests {
+		method, host, pattern, err := parseServeMuxPattern(tt.pattern)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.pattern, err)
+			continue
+		}
+		if method != tt.method || host != tt.host || pattern != tt.want {
+			t.Errorf("%q: expected %q %q %q, got %q %q %q", tt.pattern, tt.method, tt.host, tt.want, method, host, pattern)
+		}
+	}
+}
+
+// Test invalid and unsupported http.ServeMux patterns
+func TestMuxHandleFuncInvalid(t *testing.T) {
+	r := NewRouter()
+	h := func(w http.ResponseWriter, req *http.Request) {}
+
+	for _, pattern := range []string{
+		"",
+		"GET",
+		"GET users",
+		"G(T /users",
+		"{tenant}.example.com/",
+		"example.com:8080/",
+		"/a/../b",
+		"/a//b",
+		"/{$}/a",
+		"/files/{path...}/meta",
+		"/users/{id",
+		"/users/x{id}",
+		"/users/{1id}",
+		"/users/{}",
+		"/users/{id}/{id}",
+		"/a%2Fb",
+		"/a%zz",
+		"/a:b",
+		"/a*",
+	} {
+		err := r.TryHandleFunc(pattern, h)
+		if !errors.Is(err, ErrInvalidRoute) {
+			t.Errorf("%q: expected invalid route error, got %v", pattern, err)
+			continue
+		}
+		var routeErr *RouteError
+		if !errors.As(err, &routeErr) || routeErr.Pattern != pattern {
+			t.Errorf("%q: expected *RouteError for the pattern, got %#v", pattern, err)
+		}
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%q: expected HandleFunc to panic", pattern)
+				}
+			}()
+			r.HandleFunc(pattern, h)
+		}()
+	}
+
+	r.HandleFunc("GET /users/{id}", h)
+	if err := r.TryHandleFunc("GET /users/{name}", h); !errors.Is(err, ErrShadowedRoute) {
+		t.Errorf("Expected shadowed route, got %v", err)
+	}
+}
+
+// Test that routes registered with http.ServeMux patterns answer like
+// http.ServeMux
+func TestMuxHandleFunc(t *testing.T) {
+	patterns := []string{
+		"GET /users/{id}",
+		"GET /users/{id}/posts/{post}",
+		"POST /users/{id}",
+		"/files/{path...}",
+		"/static/",
+		"/{$}",
+		"GET /pages/{name}/{$}",
+		"api.example.com/v1/{resource}",
+	}
+
+	bonMux := NewRouter()
+	stdMux := http.NewServeMux()
+	for _, pattern := range patterns {
+		handler := func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte(pattern + " id=" + req.PathValue("id") + " post=" + req.PathValue("post") +
+				" path=" + req.PathValue("path") + " name=" + req.PathValue("name") + " resource=" + req.PathValue("resource")))
+		}
+		bonMux.HandleFunc(pattern, handler)
+		stdMux.HandleFunc(pattern, handler)
+	}
+	bonMux.PathValues = true
+
+	requests := []struct {
+		method string
+		target string
+	}{
+		{"GET", "/users/1"},
+		{"HEAD", "/users/1"},
+		{"POST", "/users/1"},
+		{"GET", "/users/1/posts/2"},
+		{"GET", "/files/"},
+		{"PUT", "/files/a/b.txt"},
+		{"GET", "/static/"},
+		{"GET", "/static/css/site.css"},
+		{"GET", "/"},
+		{"GET", "/pages/about/"},
+		{"GET", "http://api.example.com/v1/users"},
+		{"GET", "http://other.example.com/v1/users"},
+		{"GET", "/missing"},
+	}
+
+	for _, tt := range requests {
+		want := httptest.NewRecorder()
+		stdMux.ServeHTTP(want, httptest.NewRequest(tt.method, tt.target, nil))
+		got := httptest.NewRecorder()
+		bonMux.ServeHTTP(got, httptest.NewRequest(tt.method, tt.target, nil))
+
+		// The recorder keeps the body http.ServeMux writes for HEAD
+		if tt.method == "HEAD" {
+			want.Body.Reset()
+		}
+		if got.Code != want.Code || got.Body.String() != want.Body.String() {
+			t.Errorf("%s %s: expected %d %q, got %d %q", tt.method, tt.target, want.Code, want.Body.String(), got.Code, got.Body.String())
+		}
+	}
+}
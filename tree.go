@@ -0,0 +1,211 @@
+package bon
+
+import (
+	"net/http"
+	"strings"
+)
+
+type (
+	// dynNode is a node of the segment tree indexing the dynamic routes of a
+	// method. A path segment leads to the static child of the same text and
+	// to the child for segments with parameters, so a lookup only visits the
+	// routes whose segments fit the path. Candidates are then checked with
+	// matchPatternOptimizedInPlace.
+	dynNode struct {
+		static   map[string]*dynNode   // Children for static segments
+		fold     map[string][]*dynNode // Children for static segments by lowercase text
+		param    *dynNode              // Child for segments with parameters or a wildcard
+		routes   []dynRoute            // Routes ending at this node
+		optional []dynRoute            // Routes ending with an optional parameter below this node
+		rest     []dynRoute            // Routes ending with a trailing wildcard below this node
+	}
+
+	// dynRoute is an indexed dynamic route
+	dynRoute struct {
+		idx  int // Endpoint index
+		plen int // Length of the static prefix of the pattern
+	}
+
+	// dynSearch holds the state of a dynamic route lookup
+	dynSearch struct {
+		m         *Mux
+		data      *trieData
+		r         *http.Request
+		path      string
+		bufPtr    *[]string // Parameter buffer from paramBufferPool
+		buf       []string
+		best      *endpoint
+		bestRoute dynRoute
+		ctx       *Context // Parameters of best (nil when it has none)
+		current   *Context // Context from contextPool (reused for better matches)
+	}
+)
+
+// Create dynamic route for endpoint index
+func newDynRoute(idx int, ep *endpoint) dynRoute {
+	return dynRoute{
+		idx:  idx,
+		plen: len(getStaticPrefix(ep.matchPattern)),
+	}
+}
+
+// insert adds route for the segments of pattern after index i, the slash
+// before the next segment
+func (n *dynNode) insert(pattern string, i int, route dynRoute) {
+	end := i + 1
+	for end < len(pattern) && pattern[end] != '/' {
+		end++
+	}
+	seg, last := pattern[i+1:end], end == len(pattern)
+
+	if last && strings.IndexByte(seg, '*') >= 0 {
+		// Trailing wildcard matches the rest of the path
+		n.rest = append(n.rest, route)
+		return
+	}
+	if last && isOptionalTail(seg) {
+		// Optional parameter also matches without its segment
+		n.optional = append(n.optional, route)
+	}
+
+	var child *dynNode
+	if strings.ContainsAny(seg, ":*") {
+		if n.param == nil {
+			n.param = &dynNode{}
+		}
+		child = n.param
+	} else {
+		if child = n.static[seg]; child == nil {
+			if n.static == nil {
+				n.static = make(map[string]*dynNode)
+			}
+			child = &dynNode{}
+			n.static[seg] = child
+			if n.fold == nil {
+				n.fold = make(map[string][]*dynNode)
+			}
+			lower := strings.ToLower(seg)
+			n.fold[lower] = append(n.fold[lower], child)
+		}
+	}
+
+	if last {
+		child.routes = append(child.routes, route)
+		return
+	}
+	child.insert(pattern, end, route)
+}
+
+// foldRoutes appends to routes the routes below n whose segments fit the
+// path after index i ignoring the case of static segments (see walk)
+func (n *dynNode) foldRoutes(path string, i int, routes []dynRoute) []dynRoute {
+	if i == len(path) {
+		routes = append(routes, n.routes...)
+		return append(routes, n.optional...)
+	}
+	routes = append(routes, n.rest...)
+
+	end := i + 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	for _, child := range n.fold[strings.ToLower(path[i+1:end])] {
+		routes = child.foldRoutes(path, end, routes)
+	}
+	if n.param != nil {
+		routes = n.param.foldRoutes(path, end, routes)
+	}
+	return routes
+}
+
+// walk tries the routes below n fitting the path after index i, the slash
+// before the next segment (len(path) when the path is consumed)
+func (s *dynSearch) walk(n *dynNode, i int) {
+	if i == len(s.path) {
+		s.tryRoutes(n.routes)
+		s.tryRoutes(n.optional)
+		return
+	}
+	s.tryRoutes(n.rest)
+
+	end := i + 1
+	for end < len(s.path) && s.path[end] != '/' {
+		end++
+	}
+	if child, ok := n.static[s.path[i+1:end]]; ok {
+		s.walk(child, end)
+	}
+	if n.param != nil {
+		s.walk(n.param, end)
+	}
+}
+
+func (s *dynSearch) tryRoutes(routes []dynRoute) {
+	for _, route := range routes {
+		s.try(route)
+	}
+}
+
+// try makes route the best match when it matches the request and takes
+// priority over the current best: higher score, then shorter static prefix,
+// then earlier registration
+func (s *dynSearch) try(route dynRoute) {
+	ep := s.data.endpoints[route.idx]
+	if s.best != nil {
+		switch {
+		case ep.score != s.best.score:
+			if ep.score < s.best.score {
+				return
+			}
+		case route.plen != s.bestRoute.plen:
+			if route.plen > s.bestRoute.plen {
+				return
+			}
+		case route.idx > s.bestRoute.idx:
+			return
+		}
+	}
+
+	// Ensure buffer has enough capacity for this route's parameters
+	if needCap := len(ep.paramKeys); needCap > cap(s.buf) {
+		// Return current buffer to paramBufferPool and use a larger one
+		*s.bufPtr = s.buf[:0]
+		s.m.paramBufferPool.Put(s.bufPtr)
+		newBuf := make([]string, 0, needCap*2)
+		s.bufPtr = &newBuf
+		s.buf = newBuf
+	}
+
+	// Use the whole buffer for pattern matching
+	s.buf = s.buf[:cap(s.buf)]
+	matched, paramCount := matchPatternOptimizedInPlace(ep.matchPattern, s.path, s.buf)
+	if !matched {
+		return
+	}
+	// Constraint failure falls through to the next candidate
+	if ep.constraints != nil && !checkConstraints(ep.constraints, s.buf[:paramCount]) {
+		return
+	}
+	// Request matchers select among routes sharing the pattern
+	if s.data.variants != nil {
+		if ep = s.data.choose(route.idx, s.r); ep == nil {
+			return
+		}
+	}
+
+	if paramCount > 0 {
+		// Get context only when needed
+		if s.current == nil {
+			s.current = s.m.contextPool.Get().(*Context)
+		}
+		if !s.m.setupContextParams(s.current, s.buf[:paramCount], ep.paramKeys) {
+			// Too many parameters - skip this route
+			return
+		}
+		s.ctx = s.current
+	} else {
+		s.ctx = nil
+	}
+	s.best = ep
+	s.bestRoute = route
+}
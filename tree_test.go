@@ -0,0 +1,88 @@
+package bon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test segment tree lookup against a scan of all dynamic routes
+func TestDynamicTreeMatchesScan(t *testing.T) {
+	patterns := []string{
+		"/:a",
+		"/:a/:b",
+		"/:a/x",
+		"/x/:a",
+		"/users/:id",
+		"/users/:id<int>",
+		"/users/:id/posts/:post",
+		"/users/:id/*",
+		"/users/*rest",
+		"/files/:name.:ext",
+		"/files/:name.json",
+		"/files/*",
+		"/v:version/status",
+		"/@:handle",
+		"/docs/:lang?",
+		"/docs/:lang/:page",
+		"/a/*/c",
+		"/a/*/",
+		"/a*/b",
+		"/x*",
+		"/*",
+		"/users/:id/",
+	}
+
+	r := NewRouter()
+	for _, pattern := range patterns {
+		r.Get(pattern, func(w http.ResponseWriter, req *http.Request) {})
+	}
+	data := r.doubleArray.load()
+
+	paths := []string{
+		"/", "/x", "/x/", "/x/x", "/x/y/z", "/users", "/users/", "/users/1", "/users/abc",
+		"/users/1/", "/users/1/posts/2", "/users/1/posts", "/users//posts/2", "/files/a.json",
+		"/files/a.tar.gz", "/files/", "/files/a", "/v2/status", "/v/status", "/@gopher", "/@",
+		"/docs", "/docs/", "/docs/en", "/docs/en/intro", "/a/b/c", "/a/b/", "/abc/b", "/a/b",
+		"/xyz/w", "//", "/a//c",
+	}
+
+	for _, path := range paths {
+		req := httptest.NewRequest("GET", "/", nil)
+		got, ctx := r.lookupMethod(data, req, "GET", path)
+		if ctx != nil {
+			r.contextPool.Put(ctx.reset())
+		}
+		if want := scanDynamicRoutes(data, path); got != want {
+			t.Errorf("%s: expected %v, got %v", path, patternOf(want), patternOf(got))
+		}
+	}
+}
+
+// Best dynamic route for path by scanning every route
+func scanDynamicRoutes(data *trieData, path string) *endpoint {
+	var best *endpoint
+	var bestRoute dynRoute
+	params := make([]string, maxParamCount)
+	for idx, ep := range data.endpoints {
+		if ep.kind == nodeKindStatic {
+			continue
+		}
+		matched, n := matchPatternOptimizedInPlace(ep.matchPattern, path, params)
+		if !matched || (ep.constraints != nil && !checkConstraints(ep.constraints, params[:n])) {
+			continue
+		}
+		route := newDynRoute(idx, ep)
+		if best == nil || ep.score > best.score || (ep.score == best.score && route.plen < bestRoute.plen) {
+			best, bestRoute = ep, route
+		}
+	}
+	return best
+}
+
+func patternOf(ep *endpoint) string {
+	if ep == nil {
+		return "<nil>"
+	}
+	return ep.pattern
+}
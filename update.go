@@ -0,0 +1,247 @@
+package bon
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+type (
+	// Tx stages route additions and removals for Mux.Update
+	Tx struct {
+		state   *txState
+		options []RouteOption
+	}
+
+	// txState is the working route table shared by a Tx and its copies
+	txState struct {
+		mux       *Mux
+		endpoints []*endpoint    // Staged endpoints in order (nil when removed)
+		routes    map[string]int // Route key ("METHOD/path") -> index in endpoints
+		names     map[string]int // Route name -> index in endpoints
+		// Shape key -> indices in endpoints (built on the first strict insert)
+		shapes map[string][]int
+	}
+)
+
+// Update applies the route changes made by fn and publishes them with a
+// single atomic swap of the route table. Requests see either all of the
+// changes or none of them, and in-flight requests are not interrupted.
+//
+//	err := r.Update(func(tx *bon.Tx) error {
+//		tx.Remove(http.MethodGet, "/tenants/old/*")
+//		tx.Get("/tenants/new/*", handler)
+//		return nil
+//	})
+//
+// Nothing is published when fn returns an error or panics. Registration
+// panics for invalid routes as Mux.Handle does. fn must not register or
+// remove routes through the Mux itself.
+func (m *Mux) Update(fn func(tx *Tx) error) error {
+	m.doubleArray.mu.Lock()
+	defer m.doubleArray.mu.Unlock()
+
+	// Changes are made on top of the staged ones
+	m.doubleArray.compileLocked()
+	tx := &Tx{state: newTxState(m, m.doubleArray.data.Load())}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	// Names were checked when staged
+	newData, _ := m.doubleArray.buildData(tx.state.live())
+
+	// Store new data atomically
+	m.doubleArray.data.Store(newData)
+	return nil
+}
+
+// Create working route table from data
+func newTxState(m *Mux, data *trieData) *txState {
+	s := &txState{
+		mux:       m,
+		endpoints: append([]*endpoint(nil), data.endpoints...),
+		routes:    make(map[string]int, len(data.routes)),
+		names:     make(map[string]int, len(data.names)),
+	}
+	for key, idx := range data.routes {
+		s.routes[key] = idx
+	}
+	for name, idx := range data.names {
+		s.names[name] = idx
+	}
+	return s
+}
+
+// Staged endpoints without removed routes
+func (s *txState) live() []*endpoint {
+	endpoints := make([]*endpoint, 0, len(s.routes))
+	for _, ep := range s.endpoints {
+		if ep != nil {
+			endpoints = append(endpoints, ep)
+		}
+	}
+	return endpoints
+}
+
+// Stage ep, replacing the route with the same key. Duplicate and shadowed
+// routes are rejected when strict is true.
+func (s *txState) insert(ep *endpoint, strict bool) error {
+	key := ep.key()
+	if strict {
+		if err := s.conflict(ep); err != nil {
+			return err
+		}
+	}
+
+	// Check route name
+	if ep.name != "" {
+		if idx, ok := s.names[ep.name]; ok && s.endpoints[idx].key() != key {
+			return conflictError(ep, s.endpoints[idx], fmt.Errorf("%w: name %q", ErrDuplicateRoute, ep.name))
+		}
+	}
+
+	idx, exists := s.routes[key]
+	if exists {
+		// Replace existing route (with the same shape)
+		if name := s.endpoints[idx].name; name != "" {
+			delete(s.names, name)
+		}
+		s.endpoints[idx] = ep
+	} else {
+		// Add new route
+		idx = len(s.endpoints)
+		s.endpoints = append(s.endpoints, ep)
+		s.routes[key] = idx
+		if s.shapes != nil {
+			shape := ep.shapeKey()
+			s.shapes[shape] = append(s.shapes[shape], idx)
+		}
+	}
+	if ep.name != "" {
+		s.names[ep.name] = idx
+	}
+	return nil
+}
+
+// Unstage the routes for key, with any request matchers
+func (s *txState) remove(key string) bool {
+	removed := false
+	for idx, ep := range s.endpoints {
+		if ep == nil || ep.routeKey() != key {
+			continue
+		}
+		if ep.name != "" {
+			delete(s.names, ep.name)
+		}
+		s.endpoints[idx] = nil
+		delete(s.routes, ep.key())
+		if s.shapes != nil {
+			shape := ep.shapeKey()
+			s.shapes[shape] = slices.DeleteFunc(s.shapes[shape], func(i int) bool { return i == idx })
+		}
+		removed = true
+	}
+	return removed
+}
+
+// conflict returns an error when ep has the key of a staged route, or is
+// shadowed by one
+func (s *txState) conflict(ep *endpoint) error {
+	if idx, ok := s.routes[ep.key()]; ok {
+		return conflictError(ep, s.endpoints[idx], ErrDuplicateRoute)
+	}
+
+	if s.shapes == nil {
+		s.shapes = make(map[string][]int, len(s.routes))
+		for idx, other := range s.endpoints {
+			if other != nil {
+				shape := other.shapeKey()
+				s.shapes[shape] = append(s.shapes[shape], idx)
+			}
+		}
+	}
+	if indices := s.shapes[ep.shapeKey()]; len(indices) > 0 {
+		return conflictError(ep, s.endpoints[indices[0]], ErrShadowedRoute)
+	}
+	return nil
+}
+
+// With returns a copy of the transaction applying options to the routes registered through it
+func (tx *Tx) With(options ...RouteOption) *Tx {
+	return &Tx{
+		state:   tx.state,
+		options: append(append([]RouteOption{}, tx.options...), options...),
+	}
+}
+
+// Host returns a copy of the transaction registering and removing the
+// routes of hosts matching pattern (see Mux.Host)
+func (tx *Tx) Host(pattern string) *Tx {
+	table, err := parseHostPattern(pattern)
+	if err != nil {
+		panic("bon: " + err.Error())
+	}
+	return tx.With(withHost(table.pattern))
+}
+
+// Remove unregisters the routes registered with method and pattern, with
+// any request matchers, and reports whether one existed. The routes of a
+// host are removed through the transaction returned by Host.
+func (tx *Tx) Remove(method, pattern string) bool {
+	return tx.state.remove(routeKey(optionsHost(tx.options), method, resolvePatternPrefix(pattern)))
+}
+
+func (tx *Tx) Get(pattern string, handlerFunc http.HandlerFunc, middlewares ...Middleware) {
+	tx.Handle(http.MethodGet, pattern, handlerFunc, middlewares...)
+}
+
+func (tx *Tx) Post(pattern string, handlerFunc http.HandlerFunc, middlewares ...Middleware) {
+	tx.Handle(http.MethodPost, pattern, handlerFunc, middlewares...)
+}
+
+func (tx *Tx) Put(pattern string, handlerFunc http.HandlerFunc, middlewares ...Middleware) {
+	tx.Handle(http.MethodPut, pattern, handlerFunc, middlewares...)
+}
+
+func (tx *Tx) Delete(pattern string, handlerFunc http.HandlerFunc, middlewares ...Middleware) {
+	tx.Handle(http.MethodDelete, pattern, handlerFunc, middlewares...)
+}
+
+func (tx *Tx) Head(pattern string, handlerFunc http.HandlerFunc, middlewares ...Middleware) {
+	tx.Handle(http.MethodHead, pattern, handlerFunc, middlewares...)
+}
+
+func (tx *Tx) Options(pattern string, handlerFunc http.HandlerFunc, middlewares ...Middleware) {
+	tx.Handle(http.MethodOptions, pattern, handlerFunc, middlewares...)
+}
+
+func (tx *Tx) Patch(pattern string, handlerFunc http.HandlerFunc, middlewares ...Middleware) {
+	tx.Handle(http.MethodPatch, pattern, handlerFunc, middlewares...)
+}
+
+func (tx *Tx) Connect(pattern string, handlerFunc http.HandlerFunc, middlewares ...Middleware) {
+	tx.Handle(http.MethodConnect, pattern, handlerFunc, middlewares...)
+}
+
+func (tx *Tx) Trace(pattern string, handlerFunc http.HandlerFunc, middlewares ...Middleware) {
+	tx.Handle(http.MethodTrace, pattern, handlerFunc, middlewares...)
+}
+
+func (tx *Tx) Handle(method, pattern string, handler http.Handler, middlewares ...Middleware) {
+	ep := tx.state.mux.newEndpoint(method, pattern, handler, middlewares, tx.options)
+	if err := tx.state.insert(ep, tx.state.mux.StrictRoutes); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryHandle stages a route like Handle, but returns a *RouteError instead of
+// panicking or staging the route (see Mux.TryHandle). Conflicts are checked
+// against the staged routes.
+func (tx *Tx) TryHandle(method, pattern string, handler http.Handler, middlewares ...Middleware) error {
+	ep, err := tx.state.mux.buildEndpoint(method, pattern, handler, middlewares, tx.options)
+	if err != nil {
+		return err
+	}
+	return tx.state.insert(ep, true)
+}
@@ -0,0 +1,121 @@
+package bon
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// URL generates the escaped path of the named route. Parameters are given
+// as key-value pairs; the value of a named wildcard is given with its name,
+// and that of an unnamed wildcard with WildcardKey.
+//
+//	r.With(bon.WithName("user")).Get("/users/:id<int>", handler)
+//	path, err := r.URL("user", "id", "42") // "/users/42"
+//
+// An optional parameter that is missing or empty is omitted with its
+// segment. An error is returned when the route does not exist, or a required
+// parameter is missing, empty or fails its constraint.
+func (m *Mux) URL(name string, params ...string) (string, error) {
+	data := m.doubleArray.load()
+	idx, ok := data.names[name]
+	if !ok {
+		return "", fmt.Errorf("bon: route %q not found", name)
+	}
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("bon: odd number of parameters for route %q", name)
+	}
+	return data.endpoints[idx].buildURL(params)
+}
+
+// buildURL substitutes params (key-value pairs) into the route pattern
+func (ep *endpoint) buildURL(params []string) (string, error) {
+	var (
+		b          strings.Builder
+		pattern    = ep.matchPattern
+		paramIndex = 0
+	)
+	b.Grow(len(pattern))
+
+	for i := 0; i < len(pattern); {
+		switch pattern[i] {
+		case ':':
+			j := i + 1
+			for j < len(pattern) && !isParamNameEnd(pattern[j]) {
+				j++
+			}
+			key := pattern[i+1 : j]
+
+			value, ok := paramValue(params, key)
+			if j < len(pattern) && pattern[j] == '?' {
+				j++
+				if value == "" {
+					// Omit the segment of an optional parameter
+					path := strings.TrimSuffix(b.String(), "/")
+					if path == "" {
+						path = "/"
+					}
+					return path, nil
+				}
+			}
+			if j < len(pattern) && pattern[j] == paramEnd {
+				j++
+			}
+			if !ok {
+				return "", fmt.Errorf("bon: missing parameter %q for route %q", key, ep.name)
+			}
+			if value == "" {
+				return "", fmt.Errorf("bon: parameter %q for route %q cannot be empty", key, ep.name)
+			}
+			if paramIndex < len(ep.constraints) && ep.constraints[paramIndex] != nil && !ep.constraints[paramIndex](value) {
+				return "", fmt.Errorf("bon: parameter %q for route %q does not satisfy its constraint", key, ep.name)
+			}
+			b.WriteString(url.PathEscape(value))
+			paramIndex++
+			i = j
+
+		case '*':
+			if isTrailingWildcard(pattern, i) {
+				// Trailing wildcard keeps slashes
+				key := pattern[i+1:]
+				if key == "" {
+					key = WildcardKey
+				}
+				value, _ := paramValue(params, key)
+				b.WriteString(escapePath(strings.TrimPrefix(value, "/")))
+				i = len(pattern)
+			} else {
+				// Wildcard in the middle matches one segment
+				value, _ := paramValue(params, WildcardKey)
+				b.WriteString(url.PathEscape(value))
+				i++
+			}
+
+		default:
+			j := i
+			for j < len(pattern) && pattern[j] != ':' && pattern[j] != '*' {
+				j++
+			}
+			b.WriteString(escapePath(pattern[i:j]))
+			i = j
+		}
+	}
+
+	return b.String(), nil
+}
+
+// Find value for key in key-value pairs
+func paramValue(params []string, key string) (string, bool) {
+	for i := 0; i+1 < len(params); i += 2 {
+		if params[i] == key {
+			return params[i+1], true
+		}
+	}
+	return "", false
+}
+
+// Escape path keeping slashes
+func escapePath(p string) string {
+	u := url.URL{Path: p}
+	return u.EscapedPath()
+}
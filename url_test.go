@@ -0,0 +1,115 @@
+package bon
+
+import (
+	"net/http"
+	"testing"
+)
+
+// Test reverse URL generation for named routes
+func TestMuxURL(t *testing.T) {
+	r := NewRouter()
+	h := func(w http.ResponseWriter, req *http.Request) {}
+
+	r.With(WithName("home")).Get("/", h)
+	r.With(WithName("user")).Get("/users/:id<int>", h)
+	r.With(WithName("post")).Get("/posts/:category/:slug", h)
+	r.With(WithName("files")).Get("/files/*", h)
+	r.With(WithName("segment")).Get("/a/*/b", h)
+	r.With(WithName("download")).Get("/download/*filepath", h)
+	r.With(WithName("space")).Get("/user profile/:name", h)
+	r.With(WithName("file")).Get("/files/:name.:ext", h)
+	r.With(WithName("thumb")).Get("/img/:w<int>x:h<int>", h)
+	r.With(WithName("docs")).Get("/docs/:lang?", h)
+	r.With(WithName("top")).Get("/:lang<alpha>?", h)
+
+	api := r.Group("/api/:version")
+	api.With(WithName("api-user")).Get("/users/:id", h)
+
+	rt := r.Route().With(WithName("route"))
+	rt.Post("/items/{sku:[A-Z]{3}}", h)
+
+	tests := []struct {
+		name   string
+		params []string
+		want   string
+	}{
+		{"home", nil, "/"},
+		{"user", []string{"id", "42"}, "/users/42"},
+		{"post", []string{"slug", "hello world", "category", "go/lang"}, "/posts/go%2Flang/hello%20world"},
+		{"files", []string{"*", "css/main.css"}, "/files/css/main.css"},
+		{"files", []string{"*", "/docs/a b.pdf"}, "/files/docs/a%20b.pdf"},
+		{"files", nil, "/files/"},
+		{"segment", []string{"*", "x"}, "/a/x/b"},
+		{"download", []string{"filepath", "v1/app.tar.gz"}, "/download/v1/app.tar.gz"},
+		{"space", []string{"name", "bob"}, "/user%20profile/bob"},
+		{"file", []string{"name", "report", "ext", "pdf"}, "/files/report.pdf"},
+		{"thumb", []string{"w", "640", "h", "480"}, "/img/640x480"},
+		{"docs", []string{"lang", "en"}, "/docs/en"},
+		{"docs", nil, "/docs"},
+		{"docs", []string{"lang", ""}, "/docs"},
+		{"top", nil, "/"},
+		{"api-user", []string{"version", "v2", "id", "7"}, "/api/v2/users/7"},
+		{"route", []string{"sku", "ABC"}, "/items/ABC"},
+	}
+
+	for _, tt := range tests {
+		got, err := r.URL(tt.name, tt.params...)
+		if err != nil {
+			t.Errorf("URL(%q, %v): unexpected error %v", tt.name, tt.params, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("URL(%q, %v) = %q, want %q", tt.name, tt.params, got, tt.want)
+		}
+	}
+}
+
+// Test URL generation errors
+func TestMuxURLErrors(t *testing.T) {
+	r := NewRouter()
+	r.With(WithName("user")).Get("/users/:id<int>/:tab", func(w http.ResponseWriter, req *http.Request) {})
+
+	tests := []struct {
+		name   string
+		params []string
+	}{
+		{"unknown", nil},
+		{"user", []string{"id"}},
+		{"user", []string{"tab", "posts"}},
+		{"user", []string{"id", "abc", "tab", "posts"}},
+		{"user", []string{"id", "1", "tab", ""}},
+	}
+
+	for _, tt := range tests {
+		if got, err := r.URL(tt.name, tt.params...); err == nil {
+			t.Errorf("URL(%q, %v) = %q, expected error", tt.name, tt.params, got)
+		}
+	}
+}
+
+// Test route names are unique and follow route replacement
+func TestMuxRouteNames(t *testing.T) {
+	r := NewRouter()
+	h := func(w http.ResponseWriter, req *http.Request) {}
+
+	r.With(WithName("a")).Get("/a", h)
+
+	// Replacing the same route keeps the name valid
+	r.With(WithName("a")).Get("/a", h)
+	if got, err := r.URL("a"); err != nil || got != "/a" {
+		t.Errorf("URL(a) = %q, %v", got, err)
+	}
+
+	// Replacing a route with another name releases the old one
+	r.With(WithName("b")).Get("/a", h)
+	if _, err := r.URL("a"); err == nil {
+		t.Error("Expected old name to be released")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for duplicate route name")
+		}
+	}()
+	r.With(WithName("b")).Get("/other", h)
+}